@@ -0,0 +1,259 @@
+CREATE TABLE IF NOT EXISTS walletus_db_main.tenant_collect (
+  id bigint unsigned NOT NULL AUTO_INCREMENT,
+  tenant_id bigint unsigned NOT NULL,
+  chain varchar(50) NOT NULL,
+  collect_address varchar(255) NOT NULL,
+  native_min decimal(65,0) NOT NULL DEFAULT 0,
+  add_time datetime DEFAULT NULL,
+  update_time datetime DEFAULT NULL,
+  flag tinyint NOT NULL DEFAULT 0,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_tenant_chain (tenant_id, chain)
+);
+
+CREATE TABLE IF NOT EXISTS walletus_db_main.tenant_collect_token (
+  id bigint unsigned NOT NULL AUTO_INCREMENT,
+  tenant_id bigint unsigned NOT NULL,
+  chain varchar(50) NOT NULL,
+  token varchar(255) NOT NULL,
+  min_amount decimal(65,0) NOT NULL DEFAULT 0,
+  add_time datetime DEFAULT NULL,
+  update_time datetime DEFAULT NULL,
+  flag tinyint NOT NULL DEFAULT 0,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_tenant_chain_token (tenant_id, chain, token)
+);
+
+CREATE TABLE IF NOT EXISTS walletus_db_main.tenant_sweep (
+  id bigint unsigned NOT NULL AUTO_INCREMENT,
+  tenant_id bigint unsigned NOT NULL,
+  chain varchar(50) NOT NULL,
+  token varchar(255) NOT NULL DEFAULT '',
+  tenant_address_id bigint unsigned NOT NULL,
+  from_address varchar(255) NOT NULL,
+  to_address varchar(255) NOT NULL,
+  amount decimal(65,0) NOT NULL DEFAULT 0,
+  status varchar(50) NOT NULL,
+  tx_hash varchar(255) NOT NULL DEFAULT '',
+  err_msg varchar(1024) NOT NULL DEFAULT '',
+  add_time datetime DEFAULT NULL,
+  update_time datetime DEFAULT NULL,
+  PRIMARY KEY (id),
+  KEY idx_tenant_chain (tenant_id, chain),
+  KEY idx_address_status (tenant_address_id, status)
+);
+
+INSERT INTO walletus_db_main.admin_portal_function
+(res_uri, name, perm_code, `type`, flag, `group`)
+VALUES
+('/admin/portal/sweep/config/list', 'Sweep Config List', 'sweep:view', 'other', 0, 'tenant'),
+('/admin/portal/sweep/config/save', 'Sweep Config Save', 'sweep:edit', 'other', 0, 'tenant'),
+('/admin/portal/sweep/run', 'Sweep Run', 'sweep:edit', 'other', 0, 'tenant'),
+('/admin/portal/sweep/list', 'Sweep List', 'sweep:view', 'other', 0, 'tenant');
+
+ALTER TABLE walletus_db_main.tenant_collect
+  ADD COLUMN fee_payer varchar(255) NOT NULL DEFAULT '' AFTER native_min;
+
+CREATE TABLE IF NOT EXISTS walletus_db_main.tenant_gas_topup (
+  id bigint unsigned NOT NULL AUTO_INCREMENT,
+  tenant_id bigint unsigned NOT NULL,
+  chain varchar(50) NOT NULL,
+  token varchar(255) NOT NULL DEFAULT '',
+  tenant_address_id bigint unsigned NOT NULL,
+  fee_payer varchar(255) NOT NULL,
+  to_address varchar(255) NOT NULL,
+  required decimal(65,0) NOT NULL DEFAULT 0,
+  amount decimal(65,0) NOT NULL DEFAULT 0,
+  status varchar(50) NOT NULL,
+  tx_hash varchar(255) NOT NULL DEFAULT '',
+  err_msg varchar(1024) NOT NULL DEFAULT '',
+  add_time datetime DEFAULT NULL,
+  update_time datetime DEFAULT NULL,
+  PRIMARY KEY (id),
+  KEY idx_tenant_chain (tenant_id, chain),
+  KEY idx_address_status (tenant_address_id, status)
+);
+
+INSERT INTO walletus_db_main.admin_portal_function
+(res_uri, name, perm_code, `type`, flag, `group`)
+VALUES('/admin/portal/sweep/topup/list', 'Gas Topup List', 'sweep:view', 'other', 0, 'tenant');
+
+CREATE TABLE IF NOT EXISTS walletus_db_main.evm_nonce (
+  network varchar(50) NOT NULL,
+  address varchar(42) NOT NULL,
+  next_nonce bigint unsigned NOT NULL DEFAULT 0,
+  add_time datetime DEFAULT NULL,
+  update_time datetime DEFAULT NULL,
+  PRIMARY KEY (network, address)
+);
+
+CREATE TABLE IF NOT EXISTS walletus_db_main.evm_nonce_reservation (
+  id bigint unsigned NOT NULL AUTO_INCREMENT,
+  network varchar(50) NOT NULL,
+  address varchar(42) NOT NULL,
+  nonce bigint unsigned NOT NULL,
+  status varchar(20) NOT NULL,
+  tx_hash varchar(66) NOT NULL DEFAULT '',
+  add_time datetime DEFAULT NULL,
+  update_time datetime DEFAULT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_network_address_nonce (network, address, nonce)
+);
+
+CREATE TABLE IF NOT EXISTS walletus_db_main.tenant_withdraw (
+  id bigint unsigned NOT NULL AUTO_INCREMENT,
+  tenant_id bigint unsigned NOT NULL,
+  request_id varchar(100) NOT NULL,
+  chain varchar(50) NOT NULL,
+  token varchar(255) NOT NULL DEFAULT '',
+  tenant_address_id bigint unsigned NOT NULL,
+  from_address varchar(255) NOT NULL,
+  to_address varchar(255) NOT NULL,
+  amount decimal(65,0) NOT NULL DEFAULT 0,
+  status varchar(50) NOT NULL,
+  tx_hash varchar(255) NOT NULL DEFAULT '',
+  raw_tx text,
+  err_msg varchar(1024) NOT NULL DEFAULT '',
+  add_time datetime DEFAULT NULL,
+  update_time datetime DEFAULT NULL,
+  confirm_time datetime DEFAULT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_tenant_request (tenant_id, request_id),
+  KEY idx_status (status)
+);
+
+CREATE TABLE IF NOT EXISTS walletus_db_main.tenant_risk_rule (
+  id bigint unsigned NOT NULL AUTO_INCREMENT,
+  tenant_id bigint unsigned NOT NULL,
+  chain varchar(50) NOT NULL,
+  token varchar(255) NOT NULL DEFAULT '',
+  single_max decimal(65,0) NOT NULL DEFAULT 0,
+  daily_max decimal(65,0) NOT NULL DEFAULT 0,
+  daily_count int NOT NULL DEFAULT 0,
+  per_dest_daily_max decimal(65,0) NOT NULL DEFAULT 0,
+  strict_allowlist tinyint(1) NOT NULL DEFAULT 0,
+  add_time datetime DEFAULT NULL,
+  update_time datetime DEFAULT NULL,
+  flag tinyint NOT NULL DEFAULT 0,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_tenant_chain_token (tenant_id, chain, token)
+);
+
+CREATE TABLE IF NOT EXISTS walletus_db_main.tenant_allowlist (
+  id bigint unsigned NOT NULL AUTO_INCREMENT,
+  tenant_id bigint unsigned NOT NULL,
+  chain varchar(50) NOT NULL,
+  address varchar(255) NOT NULL,
+  label varchar(255) NOT NULL DEFAULT '',
+  add_time datetime DEFAULT NULL,
+  flag tinyint NOT NULL DEFAULT 0,
+  PRIMARY KEY (id),
+  KEY idx_tenant_chain_address (tenant_id, chain, address)
+);
+
+ALTER TABLE walletus_db_main.tenant_withdraw
+  ADD COLUMN review_reason varchar(1024) NOT NULL DEFAULT '' AFTER err_msg,
+  ADD COLUMN reviewer_id bigint unsigned NOT NULL DEFAULT 0 AFTER review_reason,
+  ADD COLUMN review_remark varchar(1024) NOT NULL DEFAULT '' AFTER reviewer_id,
+  ADD COLUMN review_time datetime DEFAULT NULL AFTER review_remark;
+
+INSERT INTO walletus_db_main.admin_portal_function
+(res_uri, name, perm_code, `type`, flag, `group`)
+VALUES
+('/admin/portal/tenant/risk/list', 'Risk Rule List', 'risk:view', 'other', 0, 'tenant'),
+('/admin/portal/tenant/risk/rule/save', 'Risk Rule Save', 'risk:edit', 'other', 0, 'tenant'),
+('/admin/portal/tenant/risk/rule/delete', 'Risk Rule Delete', 'risk:edit', 'other', 0, 'tenant'),
+('/admin/portal/tenant/risk/allowlist/save', 'Allowlist Save', 'risk:edit', 'other', 0, 'tenant'),
+('/admin/portal/tenant/risk/allowlist/delete', 'Allowlist Delete', 'risk:edit', 'other', 0, 'tenant'),
+('/admin/portal/withdraw/review/list', 'Withdraw Review List', 'withdraw:review', 'other', 0, 'tenant'),
+('/admin/portal/withdraw/review/approve', 'Withdraw Approve', 'withdraw:review', 'other', 0, 'tenant'),
+('/admin/portal/withdraw/review/reject', 'Withdraw Reject', 'withdraw:review', 'other', 0, 'tenant');
+
+CREATE TABLE IF NOT EXISTS walletus_db_main.tenant_deposit (
+  id bigint unsigned NOT NULL AUTO_INCREMENT,
+  tenant_id bigint unsigned NOT NULL,
+  tenant_address_id bigint unsigned NOT NULL,
+  chain varchar(50) NOT NULL,
+  token varchar(255) NOT NULL DEFAULT '',
+  from_address varchar(255) NOT NULL DEFAULT '',
+  to_address varchar(255) NOT NULL,
+  amount decimal(65,0) NOT NULL,
+  tx_hash varchar(128) NOT NULL,
+  log_index int NOT NULL,
+  block_number bigint unsigned NOT NULL,
+  block_hash varchar(128) NOT NULL,
+  status varchar(20) NOT NULL,
+  add_time datetime DEFAULT NULL,
+  update_time datetime DEFAULT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_chain_tx_log (chain, tx_hash, log_index),
+  KEY idx_tenant_chain (tenant_id, chain),
+  KEY idx_chain_block (chain, block_number)
+);
+
+CREATE TABLE IF NOT EXISTS walletus_db_main.scan_checkpoint (
+  chain varchar(50) NOT NULL,
+  kind varchar(20) NOT NULL,
+  height bigint unsigned NOT NULL,
+  block_hash varchar(128) NOT NULL DEFAULT '',
+  update_time datetime DEFAULT NULL,
+  PRIMARY KEY (chain, kind)
+);
+
+CREATE TABLE IF NOT EXISTS walletus_db_main.scan_block (
+  chain varchar(50) NOT NULL,
+  height bigint unsigned NOT NULL,
+  hash varchar(128) NOT NULL,
+  parent_hash varchar(128) NOT NULL,
+  add_time datetime DEFAULT NULL,
+  PRIMARY KEY (chain, height)
+);
+
+CREATE TABLE IF NOT EXISTS walletus_db_main.tenant_event (
+  id bigint unsigned NOT NULL AUTO_INCREMENT,
+  tenant_id bigint unsigned NOT NULL,
+  event_type varchar(50) NOT NULL,
+  ref_id bigint unsigned NOT NULL DEFAULT 0,
+  payload text NOT NULL,
+  add_time datetime DEFAULT NULL,
+  PRIMARY KEY (id),
+  KEY idx_tenant_time (tenant_id, add_time)
+);
+
+ALTER TABLE walletus_db_main.tenant_deposit
+  ADD COLUMN confirmations int NOT NULL DEFAULT 0 AFTER status,
+  ADD COLUMN required int NOT NULL DEFAULT 0 AFTER confirmations,
+  ADD COLUMN confirm_time datetime DEFAULT NULL AFTER required,
+  ADD KEY idx_chain_status (chain, status);
+
+ALTER TABLE walletus_db_main.tenant_event
+  ADD COLUMN status varchar(20) NOT NULL DEFAULT 'pending' AFTER payload,
+  ADD COLUMN attempts int NOT NULL DEFAULT 0 AFTER status,
+  ADD COLUMN next_time datetime DEFAULT NULL AFTER attempts,
+  ADD COLUMN deliver_time datetime DEFAULT NULL AFTER next_time,
+  ADD KEY idx_status_next (status, next_time);
+
+CREATE TABLE IF NOT EXISTS walletus_db_main.tenant_event_delivery (
+  id bigint unsigned NOT NULL AUTO_INCREMENT,
+  event_id bigint unsigned NOT NULL,
+  tenant_id bigint unsigned NOT NULL,
+  attempt int NOT NULL,
+  url varchar(255) NOT NULL,
+  status_code int NOT NULL DEFAULT 0,
+  response varchar(1024) NOT NULL DEFAULT '',
+  err_msg varchar(1024) NOT NULL DEFAULT '',
+  duration bigint NOT NULL DEFAULT 0,
+  add_time datetime DEFAULT NULL,
+  PRIMARY KEY (id),
+  KEY idx_event (event_id)
+);
+
+ALTER TABLE walletus_db_main.tenant_information
+  ADD COLUMN webhook_salt varchar(64) NOT NULL DEFAULT '' AFTER api_id;
+
+INSERT INTO walletus_db_main.admin_portal_function
+(res_uri, name, perm_code, `type`, flag, `group`)
+VALUES
+('/admin/portal/tenant/webhook/event/list', 'Webhook Event List', 'webhook:view', 'other', 0, 'tenant'),
+('/admin/portal/tenant/webhook/event/redeliver', 'Webhook Redeliver', 'webhook:edit', 'other', 0, 'tenant'),
+('/admin/portal/tenant/webhook/ping', 'Webhook Ping', 'webhook:edit', 'other', 0, 'tenant');
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/reguluswee/walletus/cmd/modapi/common"
+	"github.com/reguluswee/walletus/cmd/modapi/request"
+	"github.com/reguluswee/walletus/common/bip"
+	"github.com/reguluswee/walletus/common/chain"
+	"github.com/reguluswee/walletus/common/chain/dep"
+	"github.com/reguluswee/walletus/common/codes"
+)
+
+func FeeEstimate(c *gin.Context) {
+	var request request.FeeEstimateRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Code:      codes.CODE_ERR_REQFORMAT,
+			Msg:       "invalid request",
+			Data:      nil,
+			Timestamp: time.Now().Unix(),
+		})
+		return
+	}
+	res := common.Response{}
+	res.Timestamp = time.Now().Unix()
+
+	res.Code = codes.CODE_SUCCESS
+	res.Msg = "success"
+
+	if _, exist := c.Get("TENANTID"); !exist {
+		res.Code = codes.CODE_ERR_OBJ_NOT_FOUND
+		res.Msg = "tenant not existed"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	chainDef, err := bip.CheckValidChainCode(request.Chain)
+	if err != nil {
+		res.Code = codes.CODE_ERR_METHOD_UNSUPPORT
+		res.Msg = err.Error()
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	gw := chain.NewGateway()
+	estimate, err := gw.EstimateFee(c.Request.Context(), chain.FeeEstimateRequest{
+		Chain:   chainDef,
+		Network: chainDef.Name,
+		Query: dep.FeeQuery{
+			Token:  request.Token,
+			From:   request.From,
+			To:     request.To,
+			Amount: request.Amount,
+		},
+	})
+	if err != nil {
+		res.Code = codes.CODE_ERR_UNKNOWN
+		res.Msg = err.Error()
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	res.Data = gin.H{
+		"chain":    chainDef.Name,
+		"estimate": estimate,
+	}
+	c.JSON(http.StatusOK, res)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"net/http"
 	"strconv"
 	"time"
@@ -18,6 +19,7 @@ import (
 	"github.com/reguluswee/walletus/cmd/modapi/security"
 	"github.com/reguluswee/walletus/common/bip"
 	"github.com/reguluswee/walletus/common/chain"
+	"github.com/reguluswee/walletus/common/chain/dep"
 	"github.com/reguluswee/walletus/common/model"
 	"github.com/reguluswee/walletus/common/system"
 	"github.com/shopspring/decimal"
@@ -1197,11 +1199,44 @@ func PortalPayrollPayConfig(c *gin.Context) {
 		"payroll_settings": result,
 		"payroll_summary":  payroll,
 		"payslips":         payslips,
+		"gas_budget":       payrollGasBudget(c.Request.Context(), result, len(payslips)),
 	}
 
 	c.JSON(http.StatusOK, res)
 }
 
+// payrollGasBudget 按每笔代币转账的手续费估算整批工资的 gas 预算，估算失败时返回 nil
+func payrollGasBudget(ctx context.Context, settings PayrollSettings, count int) gin.H {
+	chainDef, err := bip.CheckValidChainCode(settings.Chain)
+	if err != nil {
+		return nil
+	}
+	gw := chain.NewGateway()
+	estimate, err := gw.EstimateFee(ctx, chain.FeeEstimateRequest{
+		Chain:   chainDef,
+		Network: chainDef.Name,
+		Query:   dep.FeeQuery{Token: settings.PayToken},
+	})
+	if err != nil {
+		log.Error("payroll fee estimate failed: ", settings.Chain, " ", err)
+		return nil
+	}
+	budget := make([]dep.FeeTier, 0, len(estimate.Tiers))
+	for _, t := range estimate.Tiers {
+		budget = append(budget, dep.FeeTier{
+			Level:       t.Level,
+			PriorityFee: t.PriorityFee,
+			MaxFee:      t.MaxFee,
+			Fee:         new(big.Int).Mul(t.Fee, big.NewInt(int64(count))),
+		})
+	}
+	return gin.H{
+		"count":    count,
+		"estimate": estimate,
+		"budget":   budget,
+	}
+}
+
 type WithWalletStaff struct {
 	model.PortalUser
 	WalletAddress string `gorm:"column:wallet_address;not null" json:"wallet_address"`
@@ -1608,24 +1643,16 @@ func PortalPayrollStatusCheck(c *gin.Context) {
 		c.JSON(http.StatusOK, res)
 		return
 	}
-	if receipt == nil {
+	switch receipt.Status {
+	case dep.TxStatusSuccess:
+	case dep.TxStatusNotFound, dep.TxStatusPending:
 		res.Code = codes.CODE_ERR_STATUS_GENERAL
 		res.Msg = "transaction not found or pending to block"
 		c.JSON(http.StatusOK, res)
 		return
-	}
-
-	m, ok := receipt.(map[string]interface{})
-	if !ok {
-		res.Code = codes.CODE_ERR_STATUS_GENERAL
-		res.Msg = "invalid receipt type"
-		c.JSON(http.StatusOK, res)
-		return
-	}
-	var status any = m["status"].(string)
-	if status != "0x1" {
+	default:
 		res.Code = codes.CODE_ERR_STATUS_GENERAL
-		res.Msg = "transaction not confirmed"
+		res.Msg = "transaction not confirmed: " + receipt.Error
 		c.JSON(http.StatusOK, res)
 		return
 	}
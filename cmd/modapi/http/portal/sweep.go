@@ -0,0 +1,370 @@
+package portal
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/reguluswee/walletus/cmd/modapi/codes"
+	"github.com/reguluswee/walletus/cmd/modapi/common"
+	"github.com/reguluswee/walletus/cmd/modapi/request"
+	"github.com/reguluswee/walletus/cmd/modapi/service"
+	"github.com/reguluswee/walletus/common/bip"
+	"github.com/reguluswee/walletus/common/model"
+	"github.com/reguluswee/walletus/common/system"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+func PortalSweepConfigList(c *gin.Context) {
+	res := common.Response{
+		Timestamp: time.Now().Unix(),
+		Code:      codes.CODE_SUCCESS,
+		Msg:       "success",
+	}
+
+	mainUser, ok := c.Get("main_user")
+	if !ok {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+	portalUser, ok := mainUser.(*model.PortalUser)
+	if !ok || portalUser == nil {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	tenantID, err := strconv.ParseUint(c.Query("tenant_id"), 10, 64)
+	if err != nil || tenantID == 0 {
+		res.Code = codes.CODE_ERR_REQFORMAT
+		res.Msg = "invalid request: tenant_id is empty"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	var db = system.GetDb()
+	var collects []model.TenantCollect
+	var tokens []model.TenantCollectToken
+	db.Where("tenant_id = ? and flag = 0", tenantID).Order("chain").Find(&collects)
+	db.Where("tenant_id = ? and flag = 0", tenantID).Order("chain, id").Find(&tokens)
+
+	res.Data = gin.H{
+		"collects": collects,
+		"tokens":   tokens,
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func PortalSweepConfigSave(c *gin.Context) {
+	var request request.PortalSweepConfigSaveRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Code:      codes.CODE_ERR_REQFORMAT,
+			Msg:       "invalid request: " + err.Error(),
+			Timestamp: time.Now().Unix(),
+		})
+		return
+	}
+
+	res := common.Response{
+		Timestamp: time.Now().Unix(),
+		Code:      codes.CODE_SUCCESS,
+		Msg:       "success",
+	}
+
+	mainUser, ok := c.Get("main_user")
+	if !ok {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+	portalUser, ok := mainUser.(*model.PortalUser)
+	if !ok || portalUser == nil {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	chainDef, err := bip.CheckValidChainCode(request.Chain)
+	if err != nil {
+		res.Code = codes.CODE_ERR_METHOD_UNSUPPORT
+		res.Msg = err.Error()
+		c.JSON(http.StatusOK, res)
+		return
+	}
+	if request.NativeMin.IsNegative() {
+		res.Code = codes.CODE_ERR_BAD_PARAMS
+		res.Msg = "native_min must not be negative"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+	for _, t := range request.Tokens {
+		if t.MinAmount.IsNegative() {
+			res.Code = codes.CODE_ERR_BAD_PARAMS
+			res.Msg = "min_amount must not be negative: " + t.Token
+			c.JSON(http.StatusOK, res)
+			return
+		}
+	}
+
+	db := system.GetDb()
+	var tenant model.Tenant
+	db.Where("id = ? and flag = 0", request.TenantID).First(&tenant)
+	if tenant.ID == 0 {
+		res.Code = codes.CODE_ERR_EXIST_OBJ
+		res.Msg = "tenant not existing"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	if request.FeePayer != "" {
+		var feePayer model.TenantAddress
+		db.Table("tenant_address ta").
+			Joins("JOIN tenant_chain tc ON ta.tenant_chain_id = tc.id").
+			Where("ta.tenant_id = ? and tc.chain = ? and ta.address_val = ?", tenant.ID, chainDef.Name, request.FeePayer).
+			Select("ta.*").
+			First(&feePayer)
+		if feePayer.ID == 0 {
+			res.Code = codes.CODE_ERR_BAD_PARAMS
+			res.Msg = "fee payer must be a tenant address on " + chainDef.Name
+			c.JSON(http.StatusOK, res)
+			return
+		}
+	}
+
+	now := time.Now()
+	var collect model.TenantCollect
+	err = db.Transaction(func(tx *gorm.DB) error {
+		tx.Where("tenant_id = ? and chain = ?", tenant.ID, chainDef.Name).First(&collect)
+		if collect.ID == 0 {
+			collect.TenantID = tenant.ID
+			collect.Chain = chainDef.Name
+			collect.AddTime = now
+		}
+		collect.CollectAddress = request.CollectAddress
+		collect.NativeMin = request.NativeMin
+		collect.FeePayer = request.FeePayer
+		collect.UpdateTime = now
+		collect.Flag = 0
+		if err := tx.Save(&collect).Error; err != nil {
+			return err
+		}
+
+		// 未出现在本次提交中的代币停用
+		keep := make([]string, 0, len(request.Tokens))
+		for _, t := range request.Tokens {
+			keep = append(keep, t.Token)
+		}
+		disable := tx.Model(&model.TenantCollectToken{}).Where("tenant_id = ? and chain = ?", tenant.ID, chainDef.Name)
+		if len(keep) > 0 {
+			disable = disable.Where("token not in ?", keep)
+		}
+		if err := disable.Updates(map[string]interface{}{"flag": 1, "update_time": now}).Error; err != nil {
+			return err
+		}
+
+		for _, t := range request.Tokens {
+			var token model.TenantCollectToken
+			tx.Where("tenant_id = ? and chain = ? and token = ?", tenant.ID, chainDef.Name, t.Token).First(&token)
+			if token.ID == 0 {
+				token.TenantID = tenant.ID
+				token.Chain = chainDef.Name
+				token.Token = t.Token
+				token.AddTime = now
+			}
+			token.MinAmount = t.MinAmount
+			token.UpdateTime = now
+			token.Flag = 0
+			if err := tx.Save(&token).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		res.Code = codes.CODE_ERR_UNKNOWN
+		res.Msg = "save sweep config error: " + err.Error()
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	res.Data = gin.H{
+		"collect": collect,
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func PortalSweepRun(c *gin.Context) {
+	var request request.PortalSweepRunRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Code:      codes.CODE_ERR_REQFORMAT,
+			Msg:       "invalid request: " + err.Error(),
+			Timestamp: time.Now().Unix(),
+		})
+		return
+	}
+
+	res := common.Response{
+		Timestamp: time.Now().Unix(),
+		Code:      codes.CODE_SUCCESS,
+		Msg:       "success",
+	}
+
+	mainUser, ok := c.Get("main_user")
+	if !ok {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+	portalUser, ok := mainUser.(*model.PortalUser)
+	if !ok || portalUser == nil {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	summary, err := service.SweepTenantChain(c.Request.Context(), request.TenantID, request.Chain)
+	if err != nil {
+		res.Code = codes.CODE_ERR_UNKNOWN
+		res.Msg = err.Error()
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	res.Data = summary
+
+	c.JSON(http.StatusOK, res)
+}
+
+func PortalSweepList(c *gin.Context) {
+	res := common.Response{
+		Timestamp: time.Now().Unix(),
+		Code:      codes.CODE_SUCCESS,
+		Msg:       "success",
+	}
+
+	mainUser, ok := c.Get("main_user")
+	if !ok {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+	portalUser, ok := mainUser.(*model.PortalUser)
+	if !ok || portalUser == nil {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > 500 {
+		size = 50
+	}
+
+	var db = system.GetDb()
+	q := db.Model(&model.TenantSweep{})
+	if tenantID := c.Query("tenant_id"); tenantID != "" {
+		q = q.Where("tenant_id = ?", tenantID)
+	}
+	if chain := c.Query("chain"); chain != "" {
+		q = q.Where("chain = ?", chain)
+	}
+	if status := c.Query("status"); status != "" {
+		q = q.Where("status = ?", status)
+	}
+
+	var total int64
+	var sweeps []model.TenantSweep
+	q.Count(&total)
+	q.Order("id DESC").Offset((page - 1) * size).Limit(size).Find(&sweeps)
+
+	res.Data = gin.H{
+		"total":  total,
+		"sweeps": sweeps,
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func PortalSweepTopupList(c *gin.Context) {
+	res := common.Response{
+		Timestamp: time.Now().Unix(),
+		Code:      codes.CODE_SUCCESS,
+		Msg:       "success",
+	}
+
+	mainUser, ok := c.Get("main_user")
+	if !ok {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+	portalUser, ok := mainUser.(*model.PortalUser)
+	if !ok || portalUser == nil {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > 500 {
+		size = 50
+	}
+
+	var db = system.GetDb()
+	q := db.Model(&model.TenantGasTopup{})
+	if tenantID := c.Query("tenant_id"); tenantID != "" {
+		q = q.Where("tenant_id = ?", tenantID)
+	}
+	if chain := c.Query("chain"); chain != "" {
+		q = q.Where("chain = ?", chain)
+	}
+
+	var total int64
+	var topups []model.TenantGasTopup
+	q.Count(&total)
+	q.Order("id DESC").Offset((page - 1) * size).Limit(size).Find(&topups)
+
+	// 已到账的补充金额按链汇总，便于核对租户 gas 支出
+	var spent []struct {
+		Chain  string          `json:"chain"`
+		Amount decimal.Decimal `json:"amount"`
+	}
+	sq := db.Model(&model.TenantGasTopup{}).Where("status = ?", model.TopupStatusConfirmed)
+	if tenantID := c.Query("tenant_id"); tenantID != "" {
+		sq = sq.Where("tenant_id = ?", tenantID)
+	}
+	sq.Select("chain, SUM(amount) AS amount").Group("chain").Scan(&spent)
+
+	res.Data = gin.H{
+		"total":  total,
+		"topups": topups,
+		"spent":  spent,
+	}
+
+	c.JSON(http.StatusOK, res)
+}
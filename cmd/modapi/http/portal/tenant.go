@@ -3,6 +3,7 @@ package portal
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/ethereum/go-ethereum/log"
@@ -11,6 +12,7 @@ import (
 	"github.com/reguluswee/walletus/cmd/modapi/common"
 	"github.com/reguluswee/walletus/cmd/modapi/request"
 	"github.com/reguluswee/walletus/cmd/modapi/security"
+	"github.com/reguluswee/walletus/cmd/modapi/service"
 	"github.com/reguluswee/walletus/common/bip"
 	"github.com/reguluswee/walletus/common/model"
 	"github.com/reguluswee/walletus/common/system"
@@ -221,6 +223,311 @@ func PortalTenantUpdate(c *gin.Context) {
 	c.JSON(http.StatusOK, res)
 }
 
+// PortalRiskList 租户的提现风控规则及白名单
+func PortalRiskList(c *gin.Context) {
+	res := common.Response{
+		Timestamp: time.Now().Unix(),
+		Code:      codes.CODE_SUCCESS,
+		Msg:       "success",
+	}
+
+	mainUser, ok := c.Get("main_user")
+	if !ok {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+	portalUser, ok := mainUser.(*model.PortalUser)
+	if !ok || portalUser == nil {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+	tenantId := c.Param("tenant_id")
+	if tenantId == "" {
+		res.Code = codes.CODE_ERR_REQFORMAT
+		res.Msg = "invalid request: tenant_id is empty"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	var db = system.GetDb()
+	var rules []model.TenantRiskRule
+	var allowlist []model.TenantAllowlist
+	db.Where("tenant_id = ? and flag = 0", tenantId).Order("chain, token").Find(&rules)
+	db.Where("tenant_id = ? and flag = 0", tenantId).Order("chain, id").Find(&allowlist)
+
+	res.Data = gin.H{
+		"rules":     rules,
+		"allowlist": allowlist,
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// PortalRiskRuleSave 新增或更新租户在某条链某币种上的风控规则，金额为最小单位，0 表示不限制
+func PortalRiskRuleSave(c *gin.Context) {
+	var request request.PortalRiskRuleSaveRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Code:      codes.CODE_ERR_REQFORMAT,
+			Msg:       "invalid request: " + err.Error(),
+			Timestamp: time.Now().Unix(),
+		})
+		return
+	}
+
+	res := common.Response{
+		Timestamp: time.Now().Unix(),
+		Code:      codes.CODE_SUCCESS,
+		Msg:       "success",
+	}
+
+	mainUser, ok := c.Get("main_user")
+	if !ok {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+	portalUser, ok := mainUser.(*model.PortalUser)
+	if !ok || portalUser == nil {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	chainDef, err := bip.CheckValidChainCode(request.Chain)
+	if err != nil {
+		res.Code = codes.CODE_ERR_BAD_PARAMS
+		res.Msg = err.Error()
+		c.JSON(http.StatusOK, res)
+		return
+	}
+	if request.SingleMax.IsNegative() || request.DailyMax.IsNegative() || request.PerDestDailyMax.IsNegative() || request.DailyCount < 0 {
+		res.Code = codes.CODE_ERR_BAD_PARAMS
+		res.Msg = "limits must not be negative"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	db := system.GetDb()
+	var tenant model.Tenant
+	db.Where("id = ? and flag = 0", request.TenantID).First(&tenant)
+	if tenant.ID == 0 {
+		res.Code = codes.CODE_ERR_EXIST_OBJ
+		res.Msg = "tenant not existing"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	now := time.Now()
+	var rule model.TenantRiskRule
+	db.Where("tenant_id = ? and chain = ? and token = ?", tenant.ID, chainDef.Name, request.Token).First(&rule)
+	if rule.ID == 0 {
+		rule.TenantID = tenant.ID
+		rule.Chain = chainDef.Name
+		rule.Token = request.Token
+		rule.AddTime = now
+	}
+	rule.SingleMax = request.SingleMax
+	rule.DailyMax = request.DailyMax
+	rule.DailyCount = request.DailyCount
+	rule.PerDestDailyMax = request.PerDestDailyMax
+	rule.StrictAllowlist = 0
+	if request.StrictAllowlist {
+		rule.StrictAllowlist = 1
+	}
+	rule.UpdateTime = now
+	rule.Flag = 0
+	if err := db.Save(&rule).Error; err != nil {
+		res.Code = codes.CODE_ERR_UNKNOWN
+		res.Msg = "save risk rule error: " + err.Error()
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	res.Data = gin.H{
+		"rule": rule,
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func PortalRiskRuleDelete(c *gin.Context) {
+	var request request.PortalRiskIDRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Code:      codes.CODE_ERR_REQFORMAT,
+			Msg:       "invalid request: " + err.Error(),
+			Timestamp: time.Now().Unix(),
+		})
+		return
+	}
+
+	res := common.Response{
+		Timestamp: time.Now().Unix(),
+		Code:      codes.CODE_SUCCESS,
+		Msg:       "success",
+	}
+
+	mainUser, ok := c.Get("main_user")
+	if !ok {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+	portalUser, ok := mainUser.(*model.PortalUser)
+	if !ok || portalUser == nil {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	db := system.GetDb()
+	var rule model.TenantRiskRule
+	db.Where("id = ? and flag = 0", request.ID).First(&rule)
+	if rule.ID == 0 {
+		res.Code = codes.CODE_ERR_EXIST_OBJ
+		res.Msg = "risk rule not existing"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	rule.Flag = 1
+	rule.UpdateTime = time.Now()
+	db.Save(&rule)
+
+	c.JSON(http.StatusOK, res)
+}
+
+// PortalAllowlistSave 添加提现白名单地址，已存在时更新备注
+func PortalAllowlistSave(c *gin.Context) {
+	var request request.PortalAllowlistSaveRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Code:      codes.CODE_ERR_REQFORMAT,
+			Msg:       "invalid request: " + err.Error(),
+			Timestamp: time.Now().Unix(),
+		})
+		return
+	}
+
+	res := common.Response{
+		Timestamp: time.Now().Unix(),
+		Code:      codes.CODE_SUCCESS,
+		Msg:       "success",
+	}
+
+	mainUser, ok := c.Get("main_user")
+	if !ok {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+	portalUser, ok := mainUser.(*model.PortalUser)
+	if !ok || portalUser == nil {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	chainDef, err := bip.CheckValidChainCode(request.Chain)
+	if err != nil {
+		res.Code = codes.CODE_ERR_BAD_PARAMS
+		res.Msg = err.Error()
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	db := system.GetDb()
+	var tenant model.Tenant
+	db.Where("id = ? and flag = 0", request.TenantID).First(&tenant)
+	if tenant.ID == 0 {
+		res.Code = codes.CODE_ERR_EXIST_OBJ
+		res.Msg = "tenant not existing"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	var entry model.TenantAllowlist
+	db.Where("tenant_id = ? and chain = ? and address = ? and flag = 0", tenant.ID, chainDef.Name, request.Address).First(&entry)
+	if entry.ID == 0 {
+		entry.TenantID = tenant.ID
+		entry.Chain = chainDef.Name
+		entry.Address = request.Address
+		entry.AddTime = time.Now()
+	}
+	entry.Label = request.Label
+	if err := db.Save(&entry).Error; err != nil {
+		res.Code = codes.CODE_ERR_UNKNOWN
+		res.Msg = "save allowlist error: " + err.Error()
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	res.Data = gin.H{
+		"entry": entry,
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+func PortalAllowlistDelete(c *gin.Context) {
+	var request request.PortalRiskIDRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Code:      codes.CODE_ERR_REQFORMAT,
+			Msg:       "invalid request: " + err.Error(),
+			Timestamp: time.Now().Unix(),
+		})
+		return
+	}
+
+	res := common.Response{
+		Timestamp: time.Now().Unix(),
+		Code:      codes.CODE_SUCCESS,
+		Msg:       "success",
+	}
+
+	mainUser, ok := c.Get("main_user")
+	if !ok {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+	portalUser, ok := mainUser.(*model.PortalUser)
+	if !ok || portalUser == nil {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	db := system.GetDb()
+	var entry model.TenantAllowlist
+	db.Where("id = ? and flag = 0", request.ID).First(&entry)
+	if entry.ID == 0 {
+		res.Code = codes.CODE_ERR_EXIST_OBJ
+		res.Msg = "allowlist entry not existing"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	entry.Flag = 1
+	db.Save(&entry)
+
+	c.JSON(http.StatusOK, res)
+}
+
 func PortalTenantDelete(c *gin.Context) {
 	var request request.PortalTenantCreateRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -320,3 +627,150 @@ func PortalTenantDetail(c *gin.Context) {
 
 	c.JSON(http.StatusOK, res)
 }
+
+// PortalWebhookEventList 租户回调事件及投递记录，tenant_id 为空时查询全部租户
+func PortalWebhookEventList(c *gin.Context) {
+	res := common.Response{
+		Timestamp: time.Now().Unix(),
+		Code:      codes.CODE_SUCCESS,
+		Msg:       "success",
+	}
+
+	mainUser, ok := c.Get("main_user")
+	if !ok {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+	portalUser, ok := mainUser.(*model.PortalUser)
+	if !ok || portalUser == nil {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	tenantID, _ := strconv.ParseUint(c.Query("tenant_id"), 10, 64)
+	startTime, _ := strconv.ParseInt(c.Query("start_time"), 10, 64)
+	endTime, _ := strconv.ParseInt(c.Query("end_time"), 10, 64)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "50"))
+
+	events, total := service.WebhookEventList(service.WebhookEventQuery{
+		TenantID:  tenantID,
+		EventType: c.Query("event_type"),
+		Status:    c.Query("status"),
+		StartTime: startTime,
+		EndTime:   endTime,
+		Page:      page,
+		Size:      size,
+	})
+	res.Data = gin.H{
+		"total":  total,
+		"events": events,
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// PortalWebhookRedeliver 重投租户的一个事件，或一段时间内的全部事件
+func PortalWebhookRedeliver(c *gin.Context) {
+	var request request.PortalWebhookRedeliverRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Code:      codes.CODE_ERR_REQFORMAT,
+			Msg:       "invalid request: " + err.Error(),
+			Timestamp: time.Now().Unix(),
+		})
+		return
+	}
+
+	res := common.Response{
+		Timestamp: time.Now().Unix(),
+		Code:      codes.CODE_SUCCESS,
+		Msg:       "success",
+	}
+
+	mainUser, ok := c.Get("main_user")
+	if !ok {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+	portalUser, ok := mainUser.(*model.PortalUser)
+	if !ok || portalUser == nil {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	n, err := service.WebhookRedeliver(request.TenantID, request.EventID, request.StartTime, request.EndTime)
+	if err != nil {
+		res.Code = codes.CODE_ERR_BAD_PARAMS
+		res.Msg = err.Error()
+		c.JSON(http.StatusOK, res)
+		return
+	}
+	res.Data = gin.H{
+		"count": n,
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// PortalWebhookPing 向租户回调地址发送测试事件
+func PortalWebhookPing(c *gin.Context) {
+	var request request.PortalWebhookPingRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Code:      codes.CODE_ERR_REQFORMAT,
+			Msg:       "invalid request: " + err.Error(),
+			Timestamp: time.Now().Unix(),
+		})
+		return
+	}
+
+	res := common.Response{
+		Timestamp: time.Now().Unix(),
+		Code:      codes.CODE_SUCCESS,
+		Msg:       "success",
+	}
+
+	mainUser, ok := c.Get("main_user")
+	if !ok {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+	portalUser, ok := mainUser.(*model.PortalUser)
+	if !ok || portalUser == nil {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	tenant, ok := service.GetTenant(request.TenantID)
+	if !ok {
+		res.Code = codes.CODE_ERR_EXIST_OBJ
+		res.Msg = "tenant not existing"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+	delivery, err := service.WebhookPing(c.Request.Context(), tenant)
+	if err != nil {
+		res.Code = codes.CODE_ERR_CONFIG
+		res.Msg = err.Error()
+		c.JSON(http.StatusOK, res)
+		return
+	}
+	res.Data = gin.H{
+		"delivery": delivery,
+	}
+
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,137 @@
+package portal
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/reguluswee/walletus/cmd/modapi/codes"
+	"github.com/reguluswee/walletus/cmd/modapi/common"
+	"github.com/reguluswee/walletus/cmd/modapi/request"
+	"github.com/reguluswee/walletus/cmd/modapi/service"
+	"github.com/reguluswee/walletus/common/model"
+	"github.com/reguluswee/walletus/common/system"
+)
+
+// PortalWithdrawReviewList 触发风控等待人工审核的提现订单
+func PortalWithdrawReviewList(c *gin.Context) {
+	res := common.Response{
+		Timestamp: time.Now().Unix(),
+		Code:      codes.CODE_SUCCESS,
+		Msg:       "success",
+	}
+
+	mainUser, ok := c.Get("main_user")
+	if !ok {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+	portalUser, ok := mainUser.(*model.PortalUser)
+	if !ok || portalUser == nil {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 || size > 500 {
+		size = 50
+	}
+
+	var db = system.GetDb()
+	q := db.Model(&model.TenantWithdraw{}).Where("status = ?", c.DefaultQuery("status", model.WithdrawStatusReview))
+	if tenantID := c.Query("tenant_id"); tenantID != "" {
+		q = q.Where("tenant_id = ?", tenantID)
+	}
+	if chain := c.Query("chain"); chain != "" {
+		q = q.Where("chain = ?", chain)
+	}
+
+	var total int64
+	var withdraws []model.TenantWithdraw
+	q.Count(&total)
+	q.Order("id").Offset((page - 1) * size).Limit(size).Find(&withdraws)
+
+	res.Data = gin.H{
+		"total":     total,
+		"withdraws": withdraws,
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// PortalWithdrawApprove 审核通过，立即签名广播
+func PortalWithdrawApprove(c *gin.Context) {
+	portalWithdrawReview(c, true)
+}
+
+// PortalWithdrawReject 审核拒绝，订单终结且不会签名
+func PortalWithdrawReject(c *gin.Context) {
+	portalWithdrawReview(c, false)
+}
+
+func portalWithdrawReview(c *gin.Context, approve bool) {
+	var request request.PortalWithdrawReviewRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Code:      codes.CODE_ERR_REQFORMAT,
+			Msg:       "invalid request: " + err.Error(),
+			Timestamp: time.Now().Unix(),
+		})
+		return
+	}
+
+	res := common.Response{
+		Timestamp: time.Now().Unix(),
+		Code:      codes.CODE_SUCCESS,
+		Msg:       "success",
+	}
+
+	mainUser, ok := c.Get("main_user")
+	if !ok {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+	portalUser, ok := mainUser.(*model.PortalUser)
+	if !ok || portalUser == nil {
+		res.Code = codes.CODE_ERR_SECURITY
+		res.Msg = "please login first"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	var order *model.TenantWithdraw
+	var err error
+	if approve {
+		order, err = service.WithdrawApprove(c.Request.Context(), request.ID, portalUser.ID, request.Remark)
+	} else {
+		order, err = service.WithdrawReject(request.ID, portalUser.ID, request.Remark)
+	}
+	if err != nil {
+		if errors.Is(err, service.ErrWithdrawNotReviewable) {
+			res.Code = codes.CODE_ERR_STATUS_GENERAL
+		} else {
+			res.Code = codes.CODE_ERR_UNKNOWN
+		}
+		res.Msg = err.Error()
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	res.Data = gin.H{
+		"withdraw": order,
+	}
+
+	c.JSON(http.StatusOK, res)
+}
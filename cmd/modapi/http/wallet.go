@@ -34,7 +34,6 @@ func WalletCreate(c *gin.Context) {
 	res.Code = codes.CODE_SUCCESS
 	res.Msg = "success"
 
-	var db = system.GetDb()
 	tenantId, exist := c.Get("TENANTID")
 	if !exist {
 		res.Code = codes.CODE_ERR_OBJ_NOT_FOUND
@@ -43,9 +42,8 @@ func WalletCreate(c *gin.Context) {
 		return
 	}
 
-	var tenant model.Tenant
-	db.Where("id = ?", tenantId).First(&tenant)
-	if tenant.ID == 0 {
+	tenant, ok := service.GetTenant(tenantId)
+	if !ok {
 		res.Code = codes.CODE_ERR_OBJ_NOT_FOUND
 		res.Msg = "tenant not found"
 		c.JSON(http.StatusOK, res)
@@ -0,0 +1,174 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/reguluswee/walletus/cmd/modapi/common"
+	"github.com/reguluswee/walletus/cmd/modapi/request"
+	"github.com/reguluswee/walletus/cmd/modapi/service"
+	"github.com/reguluswee/walletus/common/codes"
+	"github.com/reguluswee/walletus/common/model"
+)
+
+// WebhookEventList 租户的回调事件及投递记录
+func WebhookEventList(c *gin.Context) {
+	var request request.WebhookEventListRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Code:      codes.CODE_ERR_REQFORMAT,
+			Msg:       "invalid request",
+			Data:      nil,
+			Timestamp: time.Now().Unix(),
+		})
+		return
+	}
+	res := common.Response{}
+	res.Timestamp = time.Now().Unix()
+
+	res.Code = codes.CODE_SUCCESS
+	res.Msg = "success"
+
+	tenant, ok := webhookTenant(c, &res)
+	if !ok {
+		return
+	}
+
+	events, total := service.WebhookEventList(service.WebhookEventQuery{
+		TenantID:  tenant.ID,
+		EventType: request.EventType,
+		Status:    request.Status,
+		StartTime: request.StartTime,
+		EndTime:   request.EndTime,
+		Page:      request.Page,
+		Size:      request.Size,
+	})
+	res.Data = gin.H{
+		"total":  total,
+		"events": events,
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// WebhookRedeliver 重投一个事件，或一段时间内的全部事件
+func WebhookRedeliver(c *gin.Context) {
+	var request request.WebhookRedeliverRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Code:      codes.CODE_ERR_REQFORMAT,
+			Msg:       "invalid request",
+			Data:      nil,
+			Timestamp: time.Now().Unix(),
+		})
+		return
+	}
+	res := common.Response{}
+	res.Timestamp = time.Now().Unix()
+
+	res.Code = codes.CODE_SUCCESS
+	res.Msg = "success"
+
+	tenant, ok := webhookTenant(c, &res)
+	if !ok {
+		return
+	}
+
+	n, err := service.WebhookRedeliver(tenant.ID, request.EventID, request.StartTime, request.EndTime)
+	if err != nil {
+		res.Code = codes.CODE_ERR_BAD_PARAMS
+		res.Msg = err.Error()
+		c.JSON(http.StatusOK, res)
+		return
+	}
+	res.Data = gin.H{
+		"count": n,
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// WebhookPing 向当前回调地址发送测试事件，返回本次投递结果
+func WebhookPing(c *gin.Context) {
+	res := common.Response{}
+	res.Timestamp = time.Now().Unix()
+
+	res.Code = codes.CODE_SUCCESS
+	res.Msg = "success"
+
+	tenant, ok := webhookTenant(c, &res)
+	if !ok {
+		return
+	}
+
+	delivery, err := service.WebhookPing(c.Request.Context(), tenant)
+	if err != nil {
+		res.Code = codes.CODE_ERR_CONFIG
+		res.Msg = err.Error()
+		c.JSON(http.StatusOK, res)
+		return
+	}
+	res.Data = gin.H{
+		"delivery": delivery,
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// WebhookSecretQuery 当前的回调签名密钥
+func WebhookSecretQuery(c *gin.Context) {
+	webhookSecret(c, false)
+}
+
+// WebhookSecretRotate 轮换回调签名密钥，旧密钥立即失效
+func WebhookSecretRotate(c *gin.Context) {
+	webhookSecret(c, true)
+}
+
+func webhookSecret(c *gin.Context, rotate bool) {
+	res := common.Response{}
+	res.Timestamp = time.Now().Unix()
+
+	res.Code = codes.CODE_SUCCESS
+	res.Msg = "success"
+
+	tenant, ok := webhookTenant(c, &res)
+	if !ok {
+		return
+	}
+
+	var secret string
+	var err error
+	if rotate {
+		secret, err = service.WebhookRotateSecret(tenant)
+	} else {
+		secret, err = service.WebhookSecret(tenant)
+	}
+	if err != nil {
+		res.Code = codes.CODE_ERR_CONFIG
+		res.Msg = err.Error()
+		c.JSON(http.StatusOK, res)
+		return
+	}
+	res.Data = gin.H{
+		"secret": secret,
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// webhookTenant 读取拦截器写入的租户，失败时已写出响应
+func webhookTenant(c *gin.Context, res *common.Response) (model.Tenant, bool) {
+	tenantId, exist := c.Get("TENANTID")
+	if !exist {
+		res.Code = codes.CODE_ERR_OBJ_NOT_FOUND
+		res.Msg = "tenant not existed"
+		c.JSON(http.StatusOK, res)
+		return model.Tenant{}, false
+	}
+	tenant, ok := service.GetTenant(tenantId)
+	if !ok {
+		res.Code = codes.CODE_ERR_OBJ_NOT_FOUND
+		res.Msg = "tenant not found"
+		c.JSON(http.StatusOK, res)
+		return model.Tenant{}, false
+	}
+	return tenant, true
+}
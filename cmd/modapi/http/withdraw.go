@@ -0,0 +1,115 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/reguluswee/walletus/cmd/modapi/common"
+	"github.com/reguluswee/walletus/cmd/modapi/request"
+	"github.com/reguluswee/walletus/cmd/modapi/service"
+	"github.com/reguluswee/walletus/common/chain/dep"
+	"github.com/reguluswee/walletus/common/codes"
+)
+
+func WithdrawCreate(c *gin.Context) {
+	var request request.WithdrawCreateRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Code:      codes.CODE_ERR_REQFORMAT,
+			Msg:       "invalid request",
+			Data:      nil,
+			Timestamp: time.Now().Unix(),
+		})
+		return
+	}
+	res := common.Response{}
+	res.Timestamp = time.Now().Unix()
+
+	res.Code = codes.CODE_SUCCESS
+	res.Msg = "success"
+
+	tenantId, exist := c.Get("TENANTID")
+	if !exist {
+		res.Code = codes.CODE_ERR_OBJ_NOT_FOUND
+		res.Msg = "tenant not existed"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+	tenant, ok := service.GetTenant(tenantId)
+	if !ok {
+		res.Code = codes.CODE_ERR_OBJ_NOT_FOUND
+		res.Msg = "tenant not found"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	order, err := service.WithdrawCreate(c.Request.Context(), request, tenant)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrWithdrawConflict):
+			res.Code = codes.CODE_ERR_REPEAT
+		case errors.Is(err, service.ErrWithdrawAddressUnset):
+			res.Code = codes.CODE_ERR_OBJ_NOT_FOUND
+		case errors.Is(err, dep.ErrInvalidAmount):
+			res.Code = codes.CODE_ERR_BAD_PARAMS
+		default:
+			res.Code = codes.CODE_ERR_UNKNOWN
+		}
+		res.Msg = err.Error()
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	res.Data = gin.H{
+		"order": order,
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func WithdrawQuery(c *gin.Context) {
+	var request request.WithdrawQueryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, common.Response{
+			Code:      codes.CODE_ERR_REQFORMAT,
+			Msg:       "invalid request",
+			Data:      nil,
+			Timestamp: time.Now().Unix(),
+		})
+		return
+	}
+	res := common.Response{}
+	res.Timestamp = time.Now().Unix()
+
+	res.Code = codes.CODE_SUCCESS
+	res.Msg = "success"
+
+	tenantId, exist := c.Get("TENANTID")
+	if !exist {
+		res.Code = codes.CODE_ERR_OBJ_NOT_FOUND
+		res.Msg = "tenant not existed"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+	tenant, ok := service.GetTenant(tenantId)
+	if !ok {
+		res.Code = codes.CODE_ERR_OBJ_NOT_FOUND
+		res.Msg = "tenant not found"
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	order, err := service.WithdrawQuery(c.Request.Context(), tenant, request.RequestID)
+	if err != nil {
+		res.Code = codes.CODE_ERR_OBJ_NOT_FOUND
+		res.Msg = err.Error()
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	res.Data = gin.H{
+		"order": order,
+	}
+	c.JSON(http.StatusOK, res)
+}
@@ -36,6 +36,12 @@ var splitRequestingForGrants = []string{
 	"/spwapi/admin/portal/tenant/detail",
 	"/spwapi/admin/portal/payroll/staff/delete",
 	"/spwapi/admin/portal/payroll/status/check",
+	"/spwapi/admin/portal/sweep/config/list",
+	"/spwapi/admin/portal/sweep/list",
+	"/spwapi/admin/portal/sweep/topup/list",
+	"/spwapi/admin/portal/tenant/risk/list",
+	"/spwapi/admin/portal/withdraw/review/list",
+	"/spwapi/admin/portal/tenant/webhook/event/list",
 }
 
 func TokenInterceptor() gin.HandlerFunc {
@@ -11,20 +11,27 @@ import (
 	"time"
 
 	router "github.com/reguluswee/walletus/cmd/modapi/router"
+	"github.com/reguluswee/walletus/cmd/modapi/service"
 	"github.com/reguluswee/walletus/common/config"
 	"github.com/reguluswee/walletus/common/log"
+	"github.com/reguluswee/walletus/common/webhook"
 )
 
 func main() {
 	fmt.Println("starting...")
 
 	// 创建主上下文和取消函数
-	_, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	// 创建等待组，用于等待所有goroutine完成
 	var wg sync.WaitGroup
 
+	// 校准热地址 nonce，须在提现接口与归集、提现任务开始分配 nonce 之前完成
+	syncCtx, syncCancel := context.WithTimeout(ctx, time.Minute)
+	service.SyncNonces(syncCtx)
+	syncCancel()
+
 	// 启动HTTP服务器
 	server := router.Init()
 
@@ -44,6 +51,30 @@ func main() {
 		}
 	}()
 
+	// 启动自动归集
+	if interval := config.GetConfig().Sweep.Interval; interval > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			log.Info("Sweep loop starting...")
+			service.StartSweepLoop(ctx, time.Duration(interval)*time.Second)
+		}()
+	}
+
+	// 推进未终结的提现订单
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		service.StartWithdrawLoop(ctx, 30*time.Second)
+	}()
+
+	// 投递租户回调事件
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		webhook.StartDispatcher(ctx)
+	}()
+
 	// 等待中断信号
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
@@ -79,3 +79,60 @@ type PortalTenantCreateRequest struct {
 	Desc     string `json:"desc"`
 	Callback string `json:"callback"`
 }
+
+type PortalSweepTokenItem struct {
+	Token     string          `json:"token" binding:"required"`
+	MinAmount decimal.Decimal `json:"min_amount"`
+}
+
+type PortalSweepConfigSaveRequest struct {
+	TenantID       uint64                 `json:"tenant_id" binding:"required"`
+	Chain          string                 `json:"chain" binding:"required"`
+	CollectAddress string                 `json:"collect_address" binding:"required"`
+	NativeMin      decimal.Decimal        `json:"native_min"`
+	FeePayer       string                 `json:"fee_payer"`
+	Tokens         []PortalSweepTokenItem `json:"tokens"`
+}
+
+type PortalSweepRunRequest struct {
+	TenantID uint64 `json:"tenant_id" binding:"required"`
+	Chain    string `json:"chain" binding:"required"`
+}
+
+type PortalRiskRuleSaveRequest struct {
+	TenantID        uint64          `json:"tenant_id" binding:"required"`
+	Chain           string          `json:"chain" binding:"required"`
+	Token           string          `json:"token"` // 空为原生币，"*" 为该链的默认规则
+	SingleMax       decimal.Decimal `json:"single_max"`
+	DailyMax        decimal.Decimal `json:"daily_max"`
+	DailyCount      int             `json:"daily_count"`
+	PerDestDailyMax decimal.Decimal `json:"per_dest_daily_max"`
+	StrictAllowlist bool            `json:"strict_allowlist"`
+}
+
+type PortalRiskIDRequest struct {
+	ID uint64 `json:"id" binding:"required"`
+}
+
+type PortalAllowlistSaveRequest struct {
+	TenantID uint64 `json:"tenant_id" binding:"required"`
+	Chain    string `json:"chain" binding:"required"`
+	Address  string `json:"address" binding:"required"`
+	Label    string `json:"label"`
+}
+
+type PortalWithdrawReviewRequest struct {
+	ID     uint64 `json:"id" binding:"required"`
+	Remark string `json:"remark"`
+}
+
+type PortalWebhookRedeliverRequest struct {
+	TenantID  uint64 `json:"tenant_id" binding:"required"`
+	EventID   uint64 `json:"event_id"`
+	StartTime int64  `json:"start_time"`
+	EndTime   int64  `json:"end_time"`
+}
+
+type PortalWebhookPingRequest struct {
+	TenantID uint64 `json:"tenant_id" binding:"required"`
+}
@@ -19,3 +19,39 @@ type WalletBalanceQueryRequest struct {
 	Chain     string `json:"chain"`
 	Token     string `json:"token"`
 }
+
+type FeeEstimateRequest struct {
+	Chain  string `json:"chain" binding:"required"`
+	Token  string `json:"token"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Amount string `json:"amount"`
+}
+
+type WithdrawCreateRequest struct {
+	RequestID string `json:"request_id" binding:"required"`
+	Chain     string `json:"chain" binding:"required"`
+	AddressID uint32 `json:"address_id"`
+	Token     string `json:"token"`
+	To        string `json:"to" binding:"required"`
+	Amount    string `json:"amount" binding:"required"`
+}
+
+type WithdrawQueryRequest struct {
+	RequestID string `json:"request_id" binding:"required"`
+}
+
+type WebhookEventListRequest struct {
+	EventType string `json:"event_type"`
+	Status    string `json:"status"`
+	StartTime int64  `json:"start_time"`
+	EndTime   int64  `json:"end_time"`
+	Page      int    `json:"page"`
+	Size      int    `json:"size"`
+}
+
+type WebhookRedeliverRequest struct {
+	EventID   uint64 `json:"event_id"`
+	StartTime int64  `json:"start_time"`
+	EndTime   int64  `json:"end_time"`
+}
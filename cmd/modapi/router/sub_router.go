@@ -17,6 +17,14 @@ func SubRouters(e *gin.RouterGroup) {
 
 	homeGroup.POST("/was/create", http.WalletCreate)
 	homeGroup.POST("/was/balance/query", http.WalletBalanceQuery)
+	homeGroup.POST("/was/fee/estimate", http.FeeEstimate)
+	homeGroup.POST("/was/withdraw/create", http.WithdrawCreate)
+	homeGroup.POST("/was/withdraw/query", http.WithdrawQuery)
+	homeGroup.POST("/was/webhook/event/list", http.WebhookEventList)
+	homeGroup.POST("/was/webhook/event/redeliver", http.WebhookRedeliver)
+	homeGroup.POST("/was/webhook/ping", http.WebhookPing)
+	homeGroup.POST("/was/webhook/secret/query", http.WebhookSecretQuery)
+	homeGroup.POST("/was/webhook/secret/rotate", http.WebhookSecretRotate)
 
 	adminGroup := e.Group("/admin", interceptor.TokenInterceptor())
 	adminGroup.POST("/portal/login", portal.PortalLogin)
@@ -67,6 +75,24 @@ func SubRouters(e *gin.RouterGroup) {
 	adminGroup.POST("/portal/tenant/update", portal.PortalTenantUpdate)
 	adminGroup.POST("/portal/tenant/delete", portal.PortalTenantDelete)
 	adminGroup.GET("/portal/tenant/detail/:tenant_id", portal.PortalTenantDetail)
+	adminGroup.GET("/portal/tenant/risk/list/:tenant_id", portal.PortalRiskList)
+	adminGroup.POST("/portal/tenant/risk/rule/save", portal.PortalRiskRuleSave)
+	adminGroup.POST("/portal/tenant/risk/rule/delete", portal.PortalRiskRuleDelete)
+	adminGroup.POST("/portal/tenant/risk/allowlist/save", portal.PortalAllowlistSave)
+	adminGroup.POST("/portal/tenant/risk/allowlist/delete", portal.PortalAllowlistDelete)
+	adminGroup.GET("/portal/tenant/webhook/event/list", portal.PortalWebhookEventList)
+	adminGroup.POST("/portal/tenant/webhook/event/redeliver", portal.PortalWebhookRedeliver)
+	adminGroup.POST("/portal/tenant/webhook/ping", portal.PortalWebhookPing)
+
+	adminGroup.GET("/portal/withdraw/review/list", portal.PortalWithdrawReviewList)
+	adminGroup.POST("/portal/withdraw/review/approve", portal.PortalWithdrawApprove)
+	adminGroup.POST("/portal/withdraw/review/reject", portal.PortalWithdrawReject)
+
+	adminGroup.GET("/portal/sweep/config/list", portal.PortalSweepConfigList)
+	adminGroup.POST("/portal/sweep/config/save", portal.PortalSweepConfigSave)
+	adminGroup.POST("/portal/sweep/run", portal.PortalSweepRun)
+	adminGroup.GET("/portal/sweep/list", portal.PortalSweepList)
+	adminGroup.GET("/portal/sweep/topup/list", portal.PortalSweepTopupList)
 
 	adminGroup.GET("/portal/sys/payroll/settings", portal.PortalPayrollSettings)
 	adminGroup.POST("/portal/sys/payroll/settings/save", portal.PortalPayrollSettingsSave)
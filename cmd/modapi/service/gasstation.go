@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/reguluswee/walletus/common/chain"
+	"github.com/reguluswee/walletus/common/chain/dep"
+	"github.com/reguluswee/walletus/common/log"
+	"github.com/reguluswee/walletus/common/model"
+	"github.com/reguluswee/walletus/common/system"
+	"github.com/shopspring/decimal"
+)
+
+// 已广播未到账的补充在该时间内不重复发送；超过该时间仍查不到交易的补充视为被丢弃
+const gasTopupInflightWindow = 10 * time.Minute
+
+var errGasTopupPending = errors.New("gas top-up not confirmed yet")
+
+// gasStation 代币归集前为充值地址补充手续费
+// 补充广播后不在本轮等待到账（归集可能由后台接口同步触发），该地址的代币与原生币归集都推迟到下一轮；
+// 每轮先按回执更新该地址未终结的补充记录，到账后余额足够即正常归集
+type gasStation struct {
+	tenant   model.Tenant
+	chainDef dep.ChainDef
+	collect  model.TenantCollect
+	feePayer model.TenantAddress
+	gw       *chain.Gateway
+}
+
+// ensure 确认 addr 上的原生币足够支付向归集地址转出 amount 代币的手续费
+// 不足时由手续费地址补足差额，不等待到账；返回 errGasTopupPending 表示本轮应跳过该地址的归集，到账后由下一轮归集
+func (g *gasStation) ensure(ctx context.Context, addr model.TenantAddress, token string, amount *big.Int) error {
+	quoter, ok := dep.GetGasQuoter(g.chainDef)
+	if !ok {
+		return nil
+	}
+	g.reconcileTopups(ctx, addr.ID)
+
+	required, err := quoter.QuoteTokenTransferGas(ctx, g.chainDef.Name, token, addr.AddressVal, g.collect.CollectAddress, amount.String())
+	if err != nil {
+		return err
+	}
+	bal, err := g.nativeBalance(ctx, addr.AddressVal)
+	if err != nil {
+		return err
+	}
+	if bal.Cmp(required) >= 0 {
+		return nil
+	}
+	if gasTopupInflight(addr.ID) {
+		return errGasTopupPending
+	}
+
+	need := new(big.Int).Sub(required, bal)
+	if err := g.topup(ctx, addr, token, required, need); err != nil {
+		return err
+	}
+	return errGasTopupPending
+}
+
+// topup 签名、落库并广播一笔补充交易
+func (g *gasStation) topup(ctx context.Context, addr model.TenantAddress, token string, required, need *big.Int) error {
+	signer, ok := dep.GetSigner(g.chainDef)
+	if !ok {
+		return dep.ErrUnsupportedChain
+	}
+	opts := &dep.SignOptions{Key: TenantKeyRef(g.tenant, g.feePayer.DerivedPath)}
+	// 手续费地址被多个实例和任务共用，nonce 由 nonce 管理器统一分配
+	reservation, err := reserveNonce(ctx, g.chainDef, g.feePayer.AddressVal)
+	if err != nil {
+		return err
+	}
+	if reservation != nil {
+		opts.Nonce = &reservation.Nonce
+	}
+	rawTx, txHash, err := signer.SignTransferNative(ctx, g.chainDef.Name, g.feePayer.AddressVal, addr.AddressVal, need.String(), opts)
+
+	now := time.Now()
+	rec := model.TenantGasTopup{
+		TenantID:        g.tenant.ID,
+		Chain:           g.chainDef.Name,
+		Token:           token,
+		TenantAddressID: addr.ID,
+		FeePayer:        g.feePayer.AddressVal,
+		ToAddress:       addr.AddressVal,
+		Required:        decimal.NewFromBigInt(required, 0),
+		Amount:          decimal.NewFromBigInt(need, 0),
+		Status:          model.TopupStatusCreated,
+		TxHash:          txHash,
+		AddTime:         now,
+		UpdateTime:      now,
+	}
+	if err != nil {
+		rec.Status = model.TopupStatusFailed
+		rec.ErrMsg = truncateErr(err)
+	}
+	db := system.GetDb()
+	if e := db.Create(&rec).Error; e != nil {
+		log.Error("[gas] save top-up failed: ", e)
+		settleNonce(ctx, reservation, "", true)
+		return e
+	}
+	if err != nil {
+		settleNonce(ctx, reservation, "", true)
+		return err
+	}
+
+	hash, err := g.gw.Broadcast(ctx, chain.BroadcastRequest{Chain: g.chainDef, Network: g.chainDef.Name, RawTx: rawTx})
+	settleNonce(ctx, reservation, rec.TxHash, dep.IsRejected(err))
+	switch {
+	case err == nil:
+		rec.Status = model.TopupStatusBroadcast
+		if hash != "" {
+			rec.TxHash = hash
+		}
+	case dep.IsRejected(err):
+		rec.Status = model.TopupStatusFailed
+		rec.ErrMsg = truncateErr(err)
+	default:
+		// 结果不确定，交易可能已被节点接收：保留 created 与 tx_hash，在途窗口内不会重复补充，之后按回执核对
+		log.Error("[gas] top-up broadcast result unknown: ", rec.TxHash, " ", err)
+		rec.ErrMsg = truncateErr(err)
+		err = errGasTopupPending
+	}
+	rec.UpdateTime = time.Now()
+	db.Save(&rec)
+	return err
+}
+
+// reconcileTopups 按回执更新该地址 created、broadcast 状态的补充：执行成功为 confirmed，执行失败为 failed，
+// 超过在途窗口仍查不到交易的视为被丢弃，置为 failed；查询出错或仍在打包中的保持不变
+func (g *gasStation) reconcileTopups(ctx context.Context, tenantAddressID uint64) {
+	db := system.GetDb()
+	var recs []model.TenantGasTopup
+	db.Where("tenant_address_id = ? and status in ?", tenantAddressID,
+		[]string{model.TopupStatusCreated, model.TopupStatusBroadcast}).Find(&recs)
+	for i := range recs {
+		rec := &recs[i]
+		receipt, err := g.gw.GetTransaction(ctx, chain.TransactionQuery{Chain: g.chainDef, Network: g.chainDef.Name, TxHash: rec.TxHash})
+		if err != nil {
+			log.Error("[gas] query top-up failed: ", rec.TxHash, " ", err)
+			continue
+		}
+		switch receipt.Status {
+		case dep.TxStatusSuccess:
+			rec.Status = model.TopupStatusConfirmed
+			rec.ErrMsg = ""
+		case dep.TxStatusFailed:
+			rec.Status = model.TopupStatusFailed
+			rec.ErrMsg = truncateErr(errors.New("transaction failed: " + receipt.Error))
+		case dep.TxStatusNotFound:
+			if time.Since(rec.AddTime) < gasTopupInflightWindow {
+				continue
+			}
+			rec.Status = model.TopupStatusFailed
+			rec.ErrMsg = "transaction dropped"
+		default:
+			continue
+		}
+		rec.UpdateTime = time.Now()
+		db.Save(rec)
+	}
+}
+
+func (g *gasStation) nativeBalance(ctx context.Context, address string) (*big.Int, error) {
+	client, ok := dep.GetClient(g.chainDef)
+	if !ok {
+		return nil, dep.ErrUnsupportedChain
+	}
+	anchor, err := client.Anchor(ctx, g.chainDef.Name, dep.Consistency{Mode: "latest"})
+	if err != nil {
+		return nil, err
+	}
+	nb, err := client.NativeBalance(ctx, g.chainDef.Name, address, anchor)
+	if err != nil {
+		return nil, err
+	}
+	if nb == nil || nb.Amount == nil {
+		return big.NewInt(0), nil
+	}
+	return nb.Amount, nil
+}
+
+func gasTopupInflight(tenantAddressID uint64) bool {
+	var n int64
+	system.GetDb().Model(&model.TenantGasTopup{}).
+		Where("tenant_address_id = ? and status in ? and add_time > ?", tenantAddressID,
+			[]string{model.TopupStatusCreated, model.TopupStatusBroadcast}, time.Now().Add(-gasTopupInflightWindow)).
+		Count(&n)
+	return n > 0
+}
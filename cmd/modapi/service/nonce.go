@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/reguluswee/walletus/common/chain/dep"
+	"github.com/reguluswee/walletus/common/chain/evm"
+	"github.com/reguluswee/walletus/common/log"
+	"github.com/reguluswee/walletus/common/system"
+)
+
+var (
+	nonceOnce    sync.Once
+	nonceManager *evm.NonceManager
+)
+
+// getNonceManager 基于已注册的 EVM 客户端创建全局 nonce 管理器
+func getNonceManager() *evm.NonceManager {
+	nonceOnce.Do(func() {
+		for _, def := range dep.GetSupportedEVMs() {
+			client, ok := dep.GetClient(def)
+			if !ok {
+				continue
+			}
+			if src, ok := client.(evm.NonceSource); ok {
+				nonceManager = evm.NewNonceManager(system.GetDb(), src)
+				return
+			}
+		}
+	})
+	return nonceManager
+}
+
+// reserveNonce 为 EVM 链上的热地址预留 nonce，非 EVM 链返回 nil
+func reserveNonce(ctx context.Context, chainDef dep.ChainDef, address string) (*evm.NonceReservation, error) {
+	if !isEVMChain(chainDef) {
+		return nil, nil
+	}
+	nm := getNonceManager()
+	if nm == nil {
+		return nil, nil
+	}
+	return nm.Reserve(ctx, chainDef.Name, address)
+}
+
+func isEVMChain(chainDef dep.ChainDef) bool {
+	for _, def := range dep.GetSupportedEVMs() {
+		if def.Name == chainDef.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// settleNonce 签名失败或节点明确拒绝时释放预留，否则提交
+// 超时等结果不确定的广播也要提交：交易可能已进入交易池，释放后 nonce 会被另一笔交易复用
+func settleNonce(ctx context.Context, r *evm.NonceReservation, txHash string, release bool) {
+	if r == nil {
+		return
+	}
+	var err error
+	if release {
+		err = getNonceManager().Release(ctx, r)
+	} else {
+		err = getNonceManager().Commit(ctx, r, txHash)
+	}
+	if err != nil {
+		log.Error("[nonce] settle reservation failed: ", r.Network, " ", r.Address, " ", r.Nonce, " ", err)
+	}
+}
+
+// SyncNonces 启动时将所有热地址的 nonce 与链上对齐
+func SyncNonces(ctx context.Context) {
+	nm := getNonceManager()
+	if nm == nil {
+		return
+	}
+	if err := nm.SyncAll(ctx); err != nil {
+		log.Error("[nonce] sync failed: ", err)
+	}
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/reguluswee/walletus/common/chain/dep"
+	"github.com/reguluswee/walletus/common/model"
+	"github.com/reguluswee/walletus/common/system"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const riskWindow = 24 * time.Hour
+
+var ErrWithdrawNotReviewable = errors.New("withdraw order is not waiting for review")
+
+// riskUsage 24 小时窗口内已占用的额度
+type riskUsage struct {
+	Total     decimal.Decimal
+	Count     int64
+	DestTotal decimal.Decimal
+}
+
+// evaluateWithdrawRisk 按租户在该链上的规则检查订单，返回触发的规则说明，为空表示放行
+// 需在事务中调用：规则行加锁，保证并发请求下滚动额度的统计不会被绕过
+func evaluateWithdrawRisk(tx *gorm.DB, chainDef dep.ChainDef, order *model.TenantWithdraw) ([]string, error) {
+	var rules []model.TenantRiskRule
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("tenant_id = ? and chain = ? and flag = 0", order.TenantID, chainDef.Name).
+		Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+	rule, ok := pickRiskRule(rules, order.Token, isEVMChain(chainDef))
+	if !ok {
+		return nil, nil
+	}
+
+	var usage riskUsage
+	since := time.Now().Add(-riskWindow)
+	counted := []string{model.WithdrawStatusCreated, model.WithdrawStatusSigned, model.WithdrawStatusBroadcast, model.WithdrawStatusConfirmed}
+	base := func() *gorm.DB {
+		return tx.Model(&model.TenantWithdraw{}).
+			Where("tenant_id = ? and chain = ? and token = ? and status in ? and add_time >= ?", order.TenantID, chainDef.Name, order.Token, counted, since)
+	}
+	var sum struct {
+		Total decimal.Decimal
+		Count int64
+	}
+	if err := base().Select("COALESCE(SUM(amount), 0) AS total, COUNT(*) AS count").Scan(&sum).Error; err != nil {
+		return nil, err
+	}
+	usage.Total, usage.Count = sum.Total, sum.Count
+	if err := base().Where("to_address = ?", order.ToAddress).Select("COALESCE(SUM(amount), 0)").Scan(&usage.DestTotal).Error; err != nil {
+		return nil, err
+	}
+
+	allowed := true
+	if rule.StrictAllowlist == 1 {
+		var entries []model.TenantAllowlist
+		tx.Where("tenant_id = ? and chain = ? and flag = 0", order.TenantID, chainDef.Name).Find(&entries)
+		allowed = inAllowlist(chainDef, entries, order.ToAddress)
+	}
+	return checkRiskRule(rule, order.Amount, usage, allowed), nil
+}
+
+// pickRiskRule 选出对 token 生效的规则：优先币种自己的规则，其次链级默认规则，额度仍按币种分别统计
+// 链上任一规则开启白名单时，返回的规则都开启白名单；没有任何规则生效时 ok 为 false
+func pickRiskRule(rules []model.TenantRiskRule, token string, evm bool) (model.TenantRiskRule, bool) {
+	var exact, fallback *model.TenantRiskRule
+	strict := false
+	for i := range rules {
+		r := &rules[i]
+		if r.StrictAllowlist == 1 {
+			strict = true
+		}
+		switch {
+		case r.Token == token || (evm && strings.EqualFold(r.Token, token)):
+			exact = r
+		case r.Token == model.RiskRuleAnyToken:
+			fallback = r
+		}
+	}
+	var rule model.TenantRiskRule
+	switch {
+	case exact != nil:
+		rule = *exact
+	case fallback != nil:
+		rule = *fallback
+	case !strict:
+		return rule, false
+	}
+	if strict {
+		rule.StrictAllowlist = 1
+	}
+	return rule, true
+}
+
+// checkRiskRule 规则金额为 0 表示不限制
+func checkRiskRule(rule model.TenantRiskRule, amount decimal.Decimal, usage riskUsage, allowed bool) []string {
+	var reasons []string
+	if rule.SingleMax.IsPositive() && amount.GreaterThan(rule.SingleMax) {
+		reasons = append(reasons, fmt.Sprintf("amount %s exceeds single limit %s", amount, rule.SingleMax))
+	}
+	if rule.DailyMax.IsPositive() && usage.Total.Add(amount).GreaterThan(rule.DailyMax) {
+		reasons = append(reasons, fmt.Sprintf("24h volume %s exceeds limit %s", usage.Total.Add(amount), rule.DailyMax))
+	}
+	if rule.DailyCount > 0 && usage.Count+1 > int64(rule.DailyCount) {
+		reasons = append(reasons, fmt.Sprintf("24h count %d exceeds limit %d", usage.Count+1, rule.DailyCount))
+	}
+	if rule.PerDestDailyMax.IsPositive() && usage.DestTotal.Add(amount).GreaterThan(rule.PerDestDailyMax) {
+		reasons = append(reasons, fmt.Sprintf("24h volume to destination %s exceeds limit %s", usage.DestTotal.Add(amount), rule.PerDestDailyMax))
+	}
+	if rule.StrictAllowlist == 1 && !allowed {
+		reasons = append(reasons, "destination not in allowlist")
+	}
+	return reasons
+}
+
+// inAllowlist EVM 地址不区分大小写，其余链按原样比较
+func inAllowlist(chainDef dep.ChainDef, entries []model.TenantAllowlist, addr string) bool {
+	evm := isEVMChain(chainDef)
+	for _, e := range entries {
+		if e.Address == addr || (evm && strings.EqualFold(e.Address, addr)) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithdrawApprove 审核通过后按正常流程签名广播
+func WithdrawApprove(ctx context.Context, id uint64, reviewerID uint64, remark string) (*model.TenantWithdraw, error) {
+	order, err := reviewWithdraw(id, reviewerID, remark, model.WithdrawStatusCreated)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := resumeWithdraw(ctx, order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// WithdrawReject 审核拒绝，订单终结
+func WithdrawReject(id uint64, reviewerID uint64, remark string) (*model.TenantWithdraw, error) {
+	return reviewWithdraw(id, reviewerID, remark, model.WithdrawStatusRejected)
+}
+
+// reviewWithdraw 以条件更新切换审核状态，防止多人同时审核同一订单
+func reviewWithdraw(id uint64, reviewerID uint64, remark string, status string) (*model.TenantWithdraw, error) {
+	db := system.GetDb()
+	now := time.Now()
+	result := db.Model(&model.TenantWithdraw{}).
+		Where("id = ? and status = ?", id, model.WithdrawStatusReview).
+		Updates(map[string]interface{}{
+			"status":        status,
+			"reviewer_id":   reviewerID,
+			"review_remark": remark,
+			"review_time":   now,
+			"update_time":   now,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrWithdrawNotReviewable
+	}
+	var order model.TenantWithdraw
+	db.Where("id = ?", id).First(&order)
+	return &order, nil
+}
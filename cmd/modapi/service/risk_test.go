@@ -0,0 +1,91 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/reguluswee/walletus/common/chain/dep"
+	"github.com/reguluswee/walletus/common/model"
+	"github.com/shopspring/decimal"
+)
+
+func TestCheckRiskRule(t *testing.T) {
+	d := decimal.NewFromInt
+	cases := []struct {
+		name    string
+		rule    model.TenantRiskRule
+		amount  decimal.Decimal
+		usage   riskUsage
+		allowed bool
+		want    int
+	}{
+		{"zero means unlimited", model.TenantRiskRule{}, d(1_000_000), riskUsage{Total: d(1_000_000), Count: 1000, DestTotal: d(1_000_000)}, true, 0},
+		{"single max", model.TenantRiskRule{SingleMax: d(100)}, d(101), riskUsage{}, true, 1},
+		{"single max equal", model.TenantRiskRule{SingleMax: d(100)}, d(100), riskUsage{}, true, 0},
+		{"daily volume", model.TenantRiskRule{DailyMax: d(500)}, d(100), riskUsage{Total: d(401)}, true, 1},
+		{"daily count", model.TenantRiskRule{DailyCount: 3}, d(1), riskUsage{Count: 3}, true, 1},
+		{"per destination", model.TenantRiskRule{PerDestDailyMax: d(50)}, d(30), riskUsage{DestTotal: d(30)}, true, 1},
+		{"allowlist off", model.TenantRiskRule{}, d(1), riskUsage{}, false, 0},
+		{"allowlist on", model.TenantRiskRule{StrictAllowlist: 1}, d(1), riskUsage{}, false, 1},
+		{"several", model.TenantRiskRule{SingleMax: d(10), DailyCount: 1, StrictAllowlist: 1}, d(11), riskUsage{Count: 1}, false, 3},
+	}
+	for _, c := range cases {
+		if got := checkRiskRule(c.rule, c.amount, c.usage, c.allowed); len(got) != c.want {
+			t.Errorf("%s: got %v, want %d reasons", c.name, got, c.want)
+		}
+	}
+}
+
+func TestInAllowlist(t *testing.T) {
+	eth := dep.ChainDef{Name: "ETH"}
+	tron := dep.GetSupportedTron()
+	entries := []model.TenantAllowlist{
+		{Address: "0x52908400098527886E0F7030069857D2E4169EE7"},
+		{Address: "TNPeeaaFB7K9cmo4uQpcU32zGK8G1NYqeL"},
+	}
+	cases := []struct {
+		chain dep.ChainDef
+		addr  string
+		want  bool
+	}{
+		{eth, "0x52908400098527886E0F7030069857D2E4169EE7", true},
+		{eth, "0x52908400098527886e0f7030069857d2e4169ee7", true},
+		{eth, "0x8617E340B3D01FA5F11F306F4090FD50E238070D", false},
+		{tron, "TNPeeaaFB7K9cmo4uQpcU32zGK8G1NYqeL", true},
+		{tron, "tnpeeaafb7k9cmo4uqpcu32zgk8g1nyqel", false},
+	}
+	for _, c := range cases {
+		if got := inAllowlist(c.chain, entries, c.addr); got != c.want {
+			t.Errorf("%s %s: got %v want %v", c.chain.Name, c.addr, got, c.want)
+		}
+	}
+}
+
+func TestPickRiskRule(t *testing.T) {
+	usdt := "0xdAC17F958D2ee523a2206206994597C13D831ec7"
+	native := model.TenantRiskRule{ID: 1, Token: "", StrictAllowlist: 1}
+	token := model.TenantRiskRule{ID: 2, Token: usdt, SingleMax: decimal.NewFromInt(100)}
+	fallback := model.TenantRiskRule{ID: 3, Token: model.RiskRuleAnyToken, DailyCount: 5}
+
+	cases := []struct {
+		name   string
+		rules  []model.TenantRiskRule
+		token  string
+		ok     bool
+		id     uint64
+		strict uint8
+	}{
+		{"no rules", nil, usdt, false, 0, 0},
+		{"exact token, mixed case", []model.TenantRiskRule{token, fallback}, "0xdac17f958d2ee523a2206206994597c13d831ec7", true, 2, 0},
+		{"chain-wide fallback", []model.TenantRiskRule{fallback}, "0xother", true, 3, 0},
+		{"native allowlist applies to tokens", []model.TenantRiskRule{native}, usdt, true, 0, 1},
+		{"allowlist merged into token rule", []model.TenantRiskRule{native, token}, usdt, true, 2, 1},
+	}
+	for _, c := range cases {
+		rule, ok := pickRiskRule(c.rules, c.token, true)
+		got := []any{ok, rule.ID, rule.StrictAllowlist}
+		if want := []any{c.ok, c.id, c.strict}; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %v want %v", c.name, got, want)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/reguluswee/walletus/common/bip"
+	"github.com/reguluswee/walletus/common/chain"
+	"github.com/reguluswee/walletus/common/chain/dep"
+	"github.com/reguluswee/walletus/common/log"
+	"github.com/reguluswee/walletus/common/model"
+	"github.com/reguluswee/walletus/common/system"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	sweepBalanceBatch = 100
+	// 已广播的归集在该时间内视为在途，同一地址不重复归集
+	sweepInflightWindow = 10 * time.Minute
+)
+
+// SweepSummary 一次归集执行的统计
+type SweepSummary struct {
+	Scanned int `json:"scanned"`
+	Swept   int `json:"swept"`
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+}
+
+// SweepTenantChain 按租户在该链上的归集配置，将所有充值地址的余额归集到归集地址
+// 同一地址先归集代币，本轮已发出代币归集的地址不再归集原生币，避免手续费被抽干
+// 配置了手续费地址时，代币归集前会先为原生币不足的地址补充手续费
+func SweepTenantChain(ctx context.Context, tenantID uint64, chainCode string) (*SweepSummary, error) {
+	chainDef, err := bip.CheckValidChainCode(chainCode)
+	if err != nil {
+		return nil, err
+	}
+	sweeper, ok := dep.GetSweeper(chainDef)
+	if !ok {
+		return nil, dep.ErrUnsupportedChain
+	}
+
+	db := system.GetDb()
+	var tenant model.Tenant
+	db.Where("id = ? and flag = 0", tenantID).First(&tenant)
+	if tenant.ID == 0 {
+		return nil, errors.New("tenant not existing")
+	}
+	var collect model.TenantCollect
+	db.Where("tenant_id = ? and chain = ? and flag = 0", tenantID, chainDef.Name).First(&collect)
+	if collect.ID == 0 || collect.CollectAddress == "" {
+		return nil, errors.New("collect address not configured")
+	}
+	var tokens []model.TenantCollectToken
+	db.Where("tenant_id = ? and chain = ? and flag = 0", tenantID, chainDef.Name).Find(&tokens)
+
+	var tenantChain model.TenantChain
+	db.Where("tenant_id = ? and chain = ?", tenantID, chainDef.Name).First(&tenantChain)
+	if tenantChain.ID == 0 {
+		return &SweepSummary{}, nil
+	}
+
+	gw := chain.NewGateway()
+	var gs *gasStation
+	if collect.FeePayer != "" {
+		var feePayer model.TenantAddress
+		db.Where("tenant_id = ? and tenant_chain_id = ? and address_val = ?", tenantID, tenantChain.ID, collect.FeePayer).First(&feePayer)
+		if feePayer.ID == 0 {
+			return nil, errors.New("fee payer address not found")
+		}
+		gs = &gasStation{tenant: tenant, chainDef: chainDef, collect: collect, feePayer: feePayer, gw: gw}
+	}
+	var addresses []model.TenantAddress
+	db.Where("tenant_id = ? and tenant_chain_id = ?", tenantID, tenantChain.ID).Order("id").Find(&addresses)
+
+	tokenAddrs := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		tokenAddrs = append(tokenAddrs, t.Token)
+	}
+
+	summary := &SweepSummary{}
+	for start := 0; start < len(addresses); start += sweepBalanceBatch {
+		end := min(start+sweepBalanceBatch, len(addresses))
+		batch := addresses[start:end]
+
+		q := chain.BalanceQuery{
+			Chain:       chainDef,
+			Network:     chainDef.Name,
+			Consistency: dep.Consistency{Mode: "latest"},
+		}
+		if len(tokenAddrs) > 0 {
+			q.Tokens = map[string][]string{}
+		}
+		for _, a := range batch {
+			if a.AddressVal == collect.CollectAddress || a.AddressVal == collect.FeePayer {
+				continue
+			}
+			q.Addresses = append(q.Addresses, a.AddressVal)
+			if len(tokenAddrs) > 0 {
+				q.Tokens[a.AddressVal] = tokenAddrs
+			}
+		}
+		if len(q.Addresses) == 0 {
+			continue
+		}
+		balances, err := gw.GetBalances(ctx, q)
+		if err != nil {
+			log.Error("[sweep] balance query failed: ", chainDef.Name, " ", err)
+			summary.Failed += len(q.Addresses)
+			continue
+		}
+		byAddr := make(map[string]dep.BalanceResult, len(balances.Results))
+		for _, r := range balances.Results {
+			byAddr[r.Address] = r
+		}
+
+		for _, a := range batch {
+			r, ok := byAddr[a.AddressVal]
+			if !ok {
+				continue
+			}
+			summary.Scanned++
+			if sweepInflight(a.ID) {
+				summary.Skipped++
+				continue
+			}
+			key := TenantKeyRef(tenant, a.DerivedPath)
+
+			// 等待补充到账时不归集原生币，否则会转走为代币归集准备的手续费
+			tokenSent, topupPending := false, false
+			for _, t := range tokens {
+				bal := tokenBalanceOf(r.Tokens, t.Token)
+				if !reachThreshold(bal, t.MinAmount) {
+					continue
+				}
+				if gs != nil {
+					if err := gs.ensure(ctx, a, t.Token, bal.BigInt()); err != nil {
+						if errors.Is(err, errGasTopupPending) {
+							topupPending = true
+						} else {
+							log.Error("[sweep] gas top-up failed: ", a.AddressVal, " ", err)
+						}
+						summary.Skipped++
+						continue
+					}
+				}
+				switch sweepOne(ctx, gw, sweeper, chainDef, collect, a, key, t.Token) {
+				case model.SweepStatusBroadcast:
+					summary.Swept++
+					tokenSent = true
+				case model.SweepStatusFailed:
+					summary.Failed++
+				default:
+					summary.Skipped++
+				}
+			}
+			if tokenSent || topupPending || r.Native == nil || !reachThreshold(decimal.NewFromBigInt(r.Native.Amount, 0), collect.NativeMin) {
+				continue
+			}
+			switch sweepOne(ctx, gw, sweeper, chainDef, collect, a, key, "") {
+			case model.SweepStatusBroadcast:
+				summary.Swept++
+			case model.SweepStatusFailed:
+				summary.Failed++
+			default:
+				summary.Skipped++
+			}
+		}
+	}
+	return summary, nil
+}
+
+// SweepAll 对所有已配置归集地址的租户链执行一次归集
+func SweepAll(ctx context.Context) {
+	var collects []model.TenantCollect
+	system.GetDb().Where("flag = 0").Find(&collects)
+	for _, cl := range collects {
+		if ctx.Err() != nil {
+			return
+		}
+		summary, err := SweepTenantChain(ctx, cl.TenantID, cl.Chain)
+		if err != nil {
+			log.Error("[sweep] tenant ", cl.TenantID, " chain ", cl.Chain, " failed: ", err)
+			continue
+		}
+		log.Infof("[sweep] tenant %d chain %s scanned %d swept %d skipped %d failed %d",
+			cl.TenantID, cl.Chain, summary.Scanned, summary.Swept, summary.Skipped, summary.Failed)
+	}
+}
+
+// StartSweepLoop 按固定间隔执行归集，直到 ctx 取消
+func StartSweepLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			SweepAll(ctx)
+		}
+	}
+}
+
+// sweepOne 签名、落库并广播单笔归集，返回最终状态
+// 交易先以 created 状态连同 tx_hash 落库再广播，进程中断后仍可按 tx_hash 追查
+func sweepOne(ctx context.Context, gw *chain.Gateway, sweeper dep.Sweeper, chainDef dep.ChainDef, collect model.TenantCollect, addr model.TenantAddress, key dep.KeyRef, token string) string {
+	opts := &dep.SweepOptions{SignOptions: dep.SignOptions{Key: key}, Token: token}
+	// 充值地址同时可能发起提现，与提现共用 nonce 序列
+	reservation, err := reserveNonce(ctx, chainDef, addr.AddressVal)
+	if err != nil {
+		log.Error("[sweep] reserve nonce failed: ", addr.AddressVal, " ", err)
+		return model.SweepStatusFailed
+	}
+	if reservation != nil {
+		opts.Nonce = &reservation.Nonce
+	}
+	rawTx, txHash, amount, err := sweeper.BuildAndSignSweep(ctx, chainDef.Name, addr.AddressVal, collect.CollectAddress, opts)
+	if errors.Is(err, dep.ErrNothingToSweep) {
+		settleNonce(ctx, reservation, "", true)
+		return model.SweepStatusSkipped
+	}
+
+	now := time.Now()
+	job := model.TenantSweep{
+		TenantID:        collect.TenantID,
+		Chain:           chainDef.Name,
+		Token:           token,
+		TenantAddressID: addr.ID,
+		FromAddress:     addr.AddressVal,
+		ToAddress:       collect.CollectAddress,
+		Status:          model.SweepStatusCreated,
+		TxHash:          txHash,
+		AddTime:         now,
+		UpdateTime:      now,
+	}
+	if amount != "" {
+		job.Amount, _ = decimal.NewFromString(amount)
+	}
+	if err != nil {
+		job.Status = model.SweepStatusFailed
+		job.ErrMsg = truncateErr(err)
+	}
+
+	db := system.GetDb()
+	if e := db.Create(&job).Error; e != nil {
+		log.Error("[sweep] save job failed: ", e)
+		settleNonce(ctx, reservation, "", true)
+		return model.SweepStatusFailed
+	}
+	if job.Status == model.SweepStatusFailed {
+		settleNonce(ctx, reservation, "", true)
+		return job.Status
+	}
+
+	hash, err := gw.Broadcast(ctx, chain.BroadcastRequest{Chain: chainDef, Network: chainDef.Name, RawTx: rawTx})
+	settleNonce(ctx, reservation, job.TxHash, dep.IsRejected(err))
+	switch {
+	case err == nil:
+		job.Status = model.SweepStatusBroadcast
+		if hash != "" {
+			job.TxHash = hash
+		}
+	case dep.IsRejected(err):
+		job.Status = model.SweepStatusFailed
+		job.ErrMsg = truncateErr(err)
+	default:
+		// 结果不确定，交易可能已被节点接收：保留 created 与 tx_hash，在途窗口内不再归集该地址
+		log.Error("[sweep] broadcast result unknown: ", job.TxHash, " ", err)
+		job.ErrMsg = truncateErr(err)
+	}
+	job.UpdateTime = time.Now()
+	db.Save(&job)
+	return job.Status
+}
+
+func sweepInflight(tenantAddressID uint64) bool {
+	var n int64
+	system.GetDb().Model(&model.TenantSweep{}).
+		Where("tenant_address_id = ? and status in ? and add_time > ?", tenantAddressID,
+			[]string{model.SweepStatusCreated, model.SweepStatusBroadcast}, time.Now().Add(-sweepInflightWindow)).
+		Count(&n)
+	return n > 0
+}
+
+func tokenBalanceOf(tbs []dep.TokenBalance, token string) decimal.Decimal {
+	for _, tb := range tbs {
+		if strings.EqualFold(tb.Contract, token) && tb.Amount != nil {
+			return decimal.NewFromBigInt(tb.Amount, 0)
+		}
+	}
+	return decimal.Zero
+}
+
+// reachThreshold 余额为正且不低于阈值
+func reachThreshold(bal, minAmount decimal.Decimal) bool {
+	return bal.IsPositive() && bal.GreaterThanOrEqual(minAmount)
+}
+
+func truncateErr(err error) string {
+	msg := err.Error()
+	if len(msg) > 1000 {
+		msg = msg[:1000]
+	}
+	return msg
+}
@@ -7,11 +7,19 @@ import (
 
 	"github.com/reguluswee/walletus/cmd/modapi/request"
 	"github.com/reguluswee/walletus/common/bip"
+	"github.com/reguluswee/walletus/common/chain/dep"
 	"github.com/reguluswee/walletus/common/model"
 	"github.com/reguluswee/walletus/common/system"
 	"gorm.io/gorm"
 )
 
+// GetTenant 按拦截器写入的 TENANTID 查询租户
+func GetTenant(tenantID any) (model.Tenant, bool) {
+	var tenant model.Tenant
+	system.GetDb().Where("id = ?", tenantID).First(&tenant)
+	return tenant, tenant.ID != 0
+}
+
 func WalletCreate(request request.WalletCreateRequest, tenant model.Tenant) (uint64, string, error) {
 	chainDef, err := bip.CheckValidChainCode(request.Chain)
 	if err != nil {
@@ -96,3 +104,13 @@ func WalletCreate(request request.WalletCreateRequest, tenant model.Tenant) (uin
 
 	return tenantAddress.ID, addr, nil
 }
+
+// TenantKeyRef 组装租户地址的签名密钥引用
+func TenantKeyRef(tenant model.Tenant, path string) dep.KeyRef {
+	return dep.KeyRef{
+		EncMasterXprv: tenant.EncMasterXprv,
+		EncMasterSeed: tenant.EncMasterSeed,
+		KdfParams:     tenant.KdfParams,
+		Path:          path,
+	}
+}
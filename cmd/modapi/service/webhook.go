@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/reguluswee/walletus/common/model"
+	"github.com/reguluswee/walletus/common/system"
+	"github.com/reguluswee/walletus/common/webhook"
+)
+
+// 按时间范围重投的最大跨度
+const redeliverMaxRange = 7 * 24 * time.Hour
+
+var (
+	ErrCallbackUnset      = errors.New("tenant callback not configured")
+	ErrSigningKeyNotFound = errors.New("tenant api key not found")
+	ErrRedeliverRange     = errors.New("event_id or start_time and end_time within 7 days is required")
+)
+
+// WebhookEventQuery 事件列表查询条件，StartTime / EndTime 为秒级时间戳，0 表示不限
+type WebhookEventQuery struct {
+	TenantID  uint64
+	EventType string
+	Status    string
+	StartTime int64
+	EndTime   int64
+	Page      int
+	Size      int
+}
+
+// WebhookEventView 事件及其全部投递记录
+type WebhookEventView struct {
+	model.TenantEvent
+	Deliveries []model.TenantEventDelivery `json:"deliveries"`
+}
+
+// WebhookEventList 按时间倒序分页查询事件，附带每个事件的投递记录
+func WebhookEventList(q WebhookEventQuery) ([]WebhookEventView, int64) {
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.Size < 1 || q.Size > 500 {
+		q.Size = 50
+	}
+
+	db := system.GetDb()
+	tx := db.Model(&model.TenantEvent{})
+	if q.TenantID > 0 {
+		tx = tx.Where("tenant_id = ?", q.TenantID)
+	}
+	if q.EventType != "" {
+		tx = tx.Where("event_type = ?", q.EventType)
+	}
+	if q.Status != "" {
+		tx = tx.Where("status = ?", q.Status)
+	}
+	if q.StartTime > 0 {
+		tx = tx.Where("add_time >= ?", time.Unix(q.StartTime, 0))
+	}
+	if q.EndTime > 0 {
+		tx = tx.Where("add_time < ?", time.Unix(q.EndTime, 0))
+	}
+
+	var total int64
+	var events []model.TenantEvent
+	tx.Count(&total)
+	tx.Order("id DESC").Offset((q.Page - 1) * q.Size).Limit(q.Size).Find(&events)
+
+	ids := make([]uint64, 0, len(events))
+	for _, ev := range events {
+		ids = append(ids, ev.ID)
+	}
+	byEvent := make(map[uint64][]model.TenantEventDelivery, len(events))
+	if len(ids) > 0 {
+		var deliveries []model.TenantEventDelivery
+		db.Where("event_id in ?", ids).Order("id").Find(&deliveries)
+		for _, d := range deliveries {
+			byEvent[d.EventID] = append(byEvent[d.EventID], d)
+		}
+	}
+
+	out := make([]WebhookEventView, 0, len(events))
+	for _, ev := range events {
+		out = append(out, WebhookEventView{TenantEvent: ev, Deliveries: byEvent[ev.ID]})
+	}
+	return out, total
+}
+
+// WebhookRedeliver 将租户的一个事件或一段时间内的事件重新置为待投递，重试次数清零
+// eventID 为 0 时按 [startTime, endTime) 重投，跨度不超过 7 天；返回重投的事件数
+func WebhookRedeliver(tenantID, eventID uint64, startTime, endTime int64) (int64, error) {
+	tx := system.GetDb().Model(&model.TenantEvent{}).Where("tenant_id = ?", tenantID)
+	if eventID > 0 {
+		tx = tx.Where("id = ?", eventID)
+	} else {
+		start, end := time.Unix(startTime, 0), time.Unix(endTime, 0)
+		if startTime <= 0 || !end.After(start) || end.Sub(start) > redeliverMaxRange {
+			return 0, ErrRedeliverRange
+		}
+		tx = tx.Where("add_time >= ? and add_time < ?", start, end)
+	}
+	res := tx.Updates(map[string]interface{}{
+		"status":    model.EventStatusPending,
+		"attempts":  0,
+		"next_time": time.Now(),
+	})
+	return res.RowsAffected, res.Error
+}
+
+// WebhookPing 向租户回调地址发送测试事件
+func WebhookPing(ctx context.Context, tenant model.Tenant) (*model.TenantEventDelivery, error) {
+	if tenant.Callback == "" {
+		return nil, ErrCallbackUnset
+	}
+	return webhook.Ping(ctx, system.GetDb(), &tenant), nil
+}
+
+// WebhookSecret 租户当前的回调签名密钥
+func WebhookSecret(tenant model.Tenant) (string, error) {
+	var api model.SysChannel
+	system.GetDb().Where("id = ?", tenant.APIID).Limit(1).Find(&api)
+	if api.AppKey == "" {
+		return "", ErrSigningKeyNotFound
+	}
+	return webhook.SigningSecret(api.AppKey, tenant.WebhookSalt), nil
+}
+
+// WebhookRotateSecret 更换派生盐并返回新的签名密钥，之后的投递立即使用新密钥
+func WebhookRotateSecret(tenant model.Tenant) (string, error) {
+	tenant.WebhookSalt = system.GenerateNonce(32)
+	secret, err := WebhookSecret(tenant)
+	if err != nil {
+		return "", err
+	}
+	err = system.GetDb().Model(&model.Tenant{}).Where("id = ?", tenant.ID).Update("webhook_salt", tenant.WebhookSalt).Error
+	if err != nil {
+		return "", err
+	}
+	return secret, nil
+}
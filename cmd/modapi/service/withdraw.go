@@ -0,0 +1,410 @@
+package service
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/reguluswee/walletus/cmd/modapi/request"
+	"github.com/reguluswee/walletus/common/bip"
+	"github.com/reguluswee/walletus/common/chain"
+	"github.com/reguluswee/walletus/common/chain/dep"
+	"github.com/reguluswee/walletus/common/config"
+	"github.com/reguluswee/walletus/common/log"
+	"github.com/reguluswee/walletus/common/model"
+	"github.com/reguluswee/walletus/common/system"
+	"github.com/reguluswee/walletus/common/webhook"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	// created 状态超过该时间未签名的订单视为创建方已退出，由推进任务接手签名
+	withdrawCreatedStale = 5 * time.Minute
+	// 已广播但超过该时间仍未打包的订单重播 raw_tx，与 nonce 管理器判定交易被丢弃的时间一致
+	withdrawBroadcastStale = 10 * time.Minute
+)
+
+var (
+	ErrWithdrawNotFound     = errors.New("withdraw order not found")
+	ErrWithdrawConflict     = errors.New("request_id already used with different parameters")
+	ErrWithdrawAddressUnset = errors.New("tenant address not existed")
+)
+
+// WithdrawCreate 创建提现订单并签名广播
+// 相同 request_id 的重试直接返回已有订单，不会重复签名
+func WithdrawCreate(ctx context.Context, req request.WithdrawCreateRequest, tenant model.Tenant) (*model.TenantWithdraw, error) {
+	chainDef, err := bip.CheckValidChainCode(req.Chain)
+	if err != nil {
+		return nil, err
+	}
+	value, err := dep.ParseAmount(req.Amount)
+	if err != nil || value.Sign() <= 0 {
+		return nil, dep.ErrInvalidAmount
+	}
+	amount := decimal.NewFromBigInt(value, 0)
+
+	db := system.GetDb()
+	var tenantChain model.TenantChain
+	db.Where("tenant_id = ? and chain = ?", tenant.ID, chainDef.Name).First(&tenantChain)
+	var tenantAddress model.TenantAddress
+	if tenantChain.ID != 0 {
+		db.Where("tenant_id = ? and tenant_chain_id = ? and address_index = ?", tenant.ID, tenantChain.ID, req.AddressID).First(&tenantAddress)
+	}
+	if tenantAddress.ID == 0 {
+		return nil, ErrWithdrawAddressUnset
+	}
+
+	if existing, ok := findWithdraw(tenant.ID, req.RequestID); ok {
+		if !sameWithdraw(existing, chainDef.Name, tenantAddress.ID, req, amount) {
+			return nil, ErrWithdrawConflict
+		}
+		return existing, nil
+	}
+
+	now := time.Now()
+	order := &model.TenantWithdraw{
+		TenantID:        tenant.ID,
+		RequestID:       req.RequestID,
+		Chain:           chainDef.Name,
+		Token:           req.Token,
+		TenantAddressID: tenantAddress.ID,
+		FromAddress:     tenantAddress.AddressVal,
+		ToAddress:       req.To,
+		Amount:          amount,
+		Status:          model.WithdrawStatusCreated,
+		AddTime:         now,
+		UpdateTime:      now,
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		reasons, err := evaluateWithdrawRisk(tx, chainDef, order)
+		if err != nil {
+			return err
+		}
+		if len(reasons) > 0 {
+			// 触发风控的订单进入人工审核队列，不签名
+			order.Status = model.WithdrawStatusReview
+			order.ReviewReason = truncateErr(errors.New(strings.Join(reasons, "; ")))
+		}
+		return tx.Create(order).Error
+	})
+	if err != nil {
+		// 并发重试时唯一键冲突，返回先写入的订单
+		if existing, ok := findWithdraw(tenant.ID, req.RequestID); ok {
+			if !sameWithdraw(existing, chainDef.Name, tenantAddress.ID, req, amount) {
+				return nil, ErrWithdrawConflict
+			}
+			return existing, nil
+		}
+		return nil, err
+	}
+	if order.Status == model.WithdrawStatusReview {
+		log.Info("[withdraw] order held for review: ", order.ID, " ", order.ReviewReason)
+		return order, nil
+	}
+
+	if err := processWithdraw(ctx, chainDef, tenant, tenantAddress, order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// WithdrawQuery 查询提现订单，未终结的订单会先推进一次状态
+func WithdrawQuery(ctx context.Context, tenant model.Tenant, requestID string) (*model.TenantWithdraw, error) {
+	order, ok := findWithdraw(tenant.ID, requestID)
+	if !ok {
+		return nil, ErrWithdrawNotFound
+	}
+	refreshWithdraw(ctx, order)
+	return order, nil
+}
+
+// RefreshPendingWithdraws 接手长时间未签名的订单，并推进所有已签名或已广播的订单
+func RefreshPendingWithdraws(ctx context.Context) {
+	resumeStaleWithdraws(ctx)
+
+	var orders []model.TenantWithdraw
+	system.GetDb().Where("status in ?", []string{model.WithdrawStatusSigned, model.WithdrawStatusBroadcast}).
+		Order("id").Limit(500).Find(&orders)
+	for i := range orders {
+		if ctx.Err() != nil {
+			return
+		}
+		refreshWithdraw(ctx, &orders[i])
+	}
+}
+
+// StartWithdrawLoop 定期推进未终结的提现订单，直到 ctx 取消
+func StartWithdrawLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			RefreshPendingWithdraws(ctx)
+		}
+	}
+}
+
+// resumeStaleWithdraws 接手 created 状态超时的订单（创建或审核通过后进程退出、签名结果落库失败等）
+// 以条件更新 update_time 认领订单，多个实例不会重复签名
+func resumeStaleWithdraws(ctx context.Context) {
+	db := system.GetDb()
+	cutoff := time.Now().Add(-withdrawCreatedStale)
+	var orders []model.TenantWithdraw
+	db.Where("status = ? and update_time < ?", model.WithdrawStatusCreated, cutoff).Order("id").Limit(100).Find(&orders)
+	for i := range orders {
+		if ctx.Err() != nil {
+			return
+		}
+		order := &orders[i]
+		now := time.Now()
+		result := db.Model(&model.TenantWithdraw{}).
+			Where("id = ? and status = ? and update_time < ?", order.ID, model.WithdrawStatusCreated, cutoff).
+			Update("update_time", now)
+		if result.Error != nil || result.RowsAffected == 0 {
+			continue
+		}
+		order.UpdateTime = now
+		log.Info("[withdraw] resume stale order: ", order.ID)
+		if err := resumeWithdraw(ctx, order); err != nil {
+			log.Error("[withdraw] resume order failed: ", order.ID, " ", err)
+		}
+	}
+}
+
+// resumeWithdraw 加载租户与地址后签名广播 created 状态的订单
+func resumeWithdraw(ctx context.Context, order *model.TenantWithdraw) error {
+	chainDef, err := bip.CheckValidChainCode(order.Chain)
+	if err != nil {
+		failWithdraw(order, err)
+		return nil
+	}
+	tenant, ok := GetTenant(order.TenantID)
+	if !ok {
+		failWithdraw(order, errors.New("tenant not existing"))
+		return nil
+	}
+	var addr model.TenantAddress
+	system.GetDb().Where("id = ?", order.TenantAddressID).First(&addr)
+	if addr.ID == 0 {
+		failWithdraw(order, ErrWithdrawAddressUnset)
+		return nil
+	}
+	return processWithdraw(ctx, chainDef, tenant, addr, order)
+}
+
+// processWithdraw created -> signed -> broadcast，签名失败时置为 failed
+// 签名结果落库失败时返回错误，订单仍为 created，之后由 resumeStaleWithdraws 重新签名
+func processWithdraw(ctx context.Context, chainDef dep.ChainDef, tenant model.Tenant, addr model.TenantAddress, order *model.TenantWithdraw) error {
+	signer, ok := dep.GetSigner(chainDef)
+	if !ok {
+		failWithdraw(order, dep.ErrUnsupportedChain)
+		return nil
+	}
+
+	opts := &dep.SignOptions{Key: TenantKeyRef(tenant, addr.DerivedPath)}
+	reservation, err := reserveNonce(ctx, chainDef, addr.AddressVal)
+	if err != nil {
+		failWithdraw(order, err)
+		return nil
+	}
+	if reservation != nil {
+		opts.Nonce = &reservation.Nonce
+	}
+
+	var rawTx []byte
+	var txHash string
+	if order.Token == "" {
+		rawTx, txHash, err = signer.SignTransferNative(ctx, chainDef.Name, order.FromAddress, order.ToAddress, order.Amount.String(), opts)
+	} else {
+		rawTx, txHash, err = signer.SignTransferToken(ctx, chainDef.Name, order.Token, order.FromAddress, order.ToAddress, order.Amount.String(), opts)
+	}
+	if err != nil {
+		settleNonce(ctx, reservation, "", true)
+		failWithdraw(order, err)
+		return nil
+	}
+
+	// 只从 created 切换，被其他实例接手签名的订单不再广播
+	now := time.Now()
+	result := system.GetDb().Model(&model.TenantWithdraw{}).
+		Where("id = ? and status = ?", order.ID, model.WithdrawStatusCreated).
+		Updates(map[string]interface{}{
+			"status":      model.WithdrawStatusSigned,
+			"tx_hash":     txHash,
+			"raw_tx":      hex.EncodeToString(rawTx),
+			"update_time": now,
+		})
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = errors.New("withdraw order is no longer created")
+	}
+	if result.Error != nil {
+		settleNonce(ctx, reservation, "", true)
+		log.Error("[withdraw] save signed order failed: ", order.ID, " ", result.Error)
+		return result.Error
+	}
+	order.Status = model.WithdrawStatusSigned
+	order.TxHash = txHash
+	order.RawTx = hex.EncodeToString(rawTx)
+	order.UpdateTime = now
+
+	err = broadcastWithdraw(ctx, chainDef, order)
+	settleNonce(ctx, reservation, order.TxHash, dep.IsRejected(err))
+	return nil
+}
+
+// txGateway 提现用到的广播与回执查询，由 *chain.Gateway 实现
+type txGateway interface {
+	Broadcast(ctx context.Context, q chain.BroadcastRequest) (string, error)
+	GetTransaction(ctx context.Context, q chain.TransactionQuery) (*dep.TxReceipt, error)
+}
+
+// broadcastWithdraw 广播（或重播）订单并保存结果
+func broadcastWithdraw(ctx context.Context, chainDef dep.ChainDef, order *model.TenantWithdraw) error {
+	event, err := sendWithdraw(ctx, chain.NewGateway(), chainDef, order)
+	switch {
+	case event != "":
+		saveWithdrawEvent(order, event)
+	case err == nil:
+		// 重播已广播的订单，只刷新 update_time
+		if e := system.GetDb().Save(order).Error; e != nil {
+			log.Error("[withdraw] save order failed: ", order.ID, " ", e)
+		}
+	default:
+		log.Error("[withdraw] broadcast failed, will retry: ", order.ID, " ", err)
+	}
+	return err
+}
+
+// sendWithdraw 广播订单的 raw_tx 并更新内存中的状态，返回需要通知租户的事件，重播已广播的订单时不返回事件
+// 节点明确拒绝（含交易过期、nonce 已被其他交易占用）时按 tx_hash 核对：交易已在链上或交易池中说明之前的广播已成功，
+// 否则订单失败；超时、限流等结果不确定的错误不改变状态，之后原样重播同一笔交易
+func sendWithdraw(ctx context.Context, gw txGateway, chainDef dep.ChainDef, order *model.TenantWithdraw) (string, error) {
+	rawTx, err := hex.DecodeString(order.RawTx)
+	if err != nil {
+		markWithdrawFailed(order, err)
+		return model.EventWithdrawFailed, err
+	}
+	hash, err := gw.Broadcast(ctx, chain.BroadcastRequest{Chain: chainDef, Network: chainDef.Name, RawTx: rawTx})
+	if err != nil {
+		if !dep.IsRejected(err) {
+			return "", err
+		}
+		receipt, qerr := gw.GetTransaction(ctx, chain.TransactionQuery{Chain: chainDef, Network: chainDef.Name, TxHash: order.TxHash})
+		if qerr != nil {
+			return "", qerr
+		}
+		if receipt.Status == dep.TxStatusNotFound {
+			markWithdrawFailed(order, err)
+			return model.EventWithdrawFailed, err
+		}
+		hash = ""
+	}
+	event := model.EventWithdrawBroadcast
+	if order.Status == model.WithdrawStatusBroadcast {
+		event = ""
+	}
+	order.Status = model.WithdrawStatusBroadcast
+	if hash != "" {
+		order.TxHash = hash
+	}
+	order.UpdateTime = time.Now()
+	return event, nil
+}
+
+// refreshWithdraw 重播未广播成功的订单，并根据链上回执确认已广播的订单
+// 已广播的订单超过 withdrawBroadcastStale 仍未打包时重播 raw_tx，交易已过期或 nonce 已被占用时订单失败
+func refreshWithdraw(ctx context.Context, order *model.TenantWithdraw) {
+	chainDef, err := bip.CheckValidChainCode(order.Chain)
+	if err != nil {
+		return
+	}
+	switch order.Status {
+	case model.WithdrawStatusSigned:
+		_ = broadcastWithdraw(ctx, chainDef, order)
+	case model.WithdrawStatusBroadcast:
+		gw := chain.NewGateway()
+		cs, required := withdrawConsistency(chainDef, order.Token)
+		receipt, err := gw.GetTransaction(ctx, chain.TransactionQuery{Chain: chainDef, Network: chainDef.Name, TxHash: order.TxHash, Consistency: cs})
+		if err != nil {
+			return
+		}
+		// 执行结果在达到确认要求前仍可能被链重组回滚
+		if receipt.Final() && receipt.Confirmations < required {
+			return
+		}
+		switch receipt.Status {
+		case dep.TxStatusSuccess:
+			now := time.Now()
+			order.Status = model.WithdrawStatusConfirmed
+			order.ConfirmTime = &now
+			order.UpdateTime = now
+			saveWithdrawEvent(order, model.EventWithdrawConfirmed)
+		case dep.TxStatusFailed:
+			failWithdraw(order, errors.New("transaction failed: "+receipt.Error))
+		default:
+			if time.Since(order.UpdateTime) >= withdrawBroadcastStale {
+				log.Info("[withdraw] rebroadcast stale order: ", order.ID, " ", order.TxHash)
+				_ = broadcastWithdraw(ctx, chainDef, order)
+			}
+		}
+	}
+}
+
+// withdrawConsistency 查询回执的锚点与需要的确认数，与入账确认使用同一份配置
+// 要求最终确认时以最终确认的高度为锚点，交易位于锚点之内（1 个确认）即视为最终确认；否则以链配置的一致性为锚点按区块数计数
+func withdrawConsistency(chainDef dep.ChainDef, token string) (dep.Consistency, uint64) {
+	cc := config.GetRpcConfig(chainDef.Name)
+	if cc == nil {
+		return dep.Consistency{}, 1
+	}
+	c := cc.GetConfirm(token)
+	if c.Finality == "finalized" {
+		// TRON 的最终确认为固化块
+		if chainDef.Name == dep.GetSupportedTron().Name {
+			return dep.Consistency{Mode: "latest_solid"}, 1
+		}
+		return dep.Consistency{Mode: "finalized"}, 1
+	}
+	return dep.Consistency{Mode: cc.Consistency}, uint64(max(c.Blocks, 1))
+}
+
+func failWithdraw(order *model.TenantWithdraw, err error) {
+	markWithdrawFailed(order, err)
+	saveWithdrawEvent(order, model.EventWithdrawFailed)
+}
+
+func markWithdrawFailed(order *model.TenantWithdraw, err error) {
+	order.Status = model.WithdrawStatusFailed
+	order.ErrMsg = truncateErr(err)
+	order.UpdateTime = time.Now()
+}
+
+// saveWithdrawEvent 保存订单状态，并在同一事务中记录通知租户的事件
+func saveWithdrawEvent(order *model.TenantWithdraw, eventType string) {
+	err := system.GetDb().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(order).Error; err != nil {
+			return err
+		}
+		return webhook.Emit(tx, order.TenantID, eventType, order.ID, order)
+	})
+	if err != nil {
+		log.Error("[withdraw] save order failed: ", order.ID, " ", order.Status, " ", err)
+	}
+}
+
+func findWithdraw(tenantID uint64, requestID string) (*model.TenantWithdraw, bool) {
+	var order model.TenantWithdraw
+	system.GetDb().Where("tenant_id = ? and request_id = ?", tenantID, requestID).First(&order)
+	return &order, order.ID != 0
+}
+
+// sameWithdraw 重试的参数（含出款地址）与已有订单一致
+func sameWithdraw(o *model.TenantWithdraw, chainName string, tenantAddressID uint64, req request.WithdrawCreateRequest, amount decimal.Decimal) bool {
+	return o.Chain == chainName && o.TenantAddressID == tenantAddressID && o.Token == req.Token && o.ToAddress == req.To && o.Amount.Equal(amount)
+}
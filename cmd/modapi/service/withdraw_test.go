@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/reguluswee/walletus/cmd/modapi/request"
+	"github.com/reguluswee/walletus/common/chain"
+	"github.com/reguluswee/walletus/common/chain/dep"
+	"github.com/reguluswee/walletus/common/model"
+	"github.com/shopspring/decimal"
+)
+
+// stubGateway 广播错误与真实客户端一样经 ClassifyBroadcastError 包装
+// receipt 为空时查不到交易
+type stubGateway struct {
+	err     error
+	receipt string
+}
+
+func (s stubGateway) Broadcast(ctx context.Context, q chain.BroadcastRequest) (string, error) {
+	if s.err != nil {
+		return "0xabc", dep.ClassifyBroadcastError(s.err.Error())
+	}
+	return "0xabc", nil
+}
+
+func (s stubGateway) GetTransaction(ctx context.Context, q chain.TransactionQuery) (*dep.TxReceipt, error) {
+	status := s.receipt
+	if status == "" {
+		status = dep.TxStatusNotFound
+	}
+	return &dep.TxReceipt{TxHash: q.TxHash, Status: status}, nil
+}
+
+func TestSendWithdraw(t *testing.T) {
+	signed, broadcast, failed := model.WithdrawStatusSigned, model.WithdrawStatusBroadcast, model.WithdrawStatusFailed
+	cases := []struct {
+		name      string
+		from      string
+		err       error
+		receipt   string
+		status    string
+		event     string
+		releasing bool
+	}{
+		{"timeout", signed, context.DeadlineExceeded, "", signed, "", false},
+		{"rate limited", signed, errors.New("rpc rate limit exceeded for ETH"), "", signed, "", false},
+		{"nonce too low", signed, errors.New("nonce too low"), "", failed, model.EventWithdrawFailed, true},
+		{"insufficient funds", signed, errors.New("insufficient funds for gas * price + value"), "", failed, model.EventWithdrawFailed, true},
+		{"expired", signed, errors.New("TRANSACTION_EXPIRATION_ERROR"), "", failed, model.EventWithdrawFailed, true},
+		{"expired but landed", signed, errors.New("Blockhash not found"), dep.TxStatusSuccess, broadcast, model.EventWithdrawBroadcast, false},
+		{"ok", signed, nil, "", broadcast, model.EventWithdrawBroadcast, false},
+		{"rebroadcast ok", broadcast, nil, "", broadcast, "", false},
+		{"rebroadcast nonce taken", broadcast, errors.New("nonce too low"), "", failed, model.EventWithdrawFailed, true},
+		{"rebroadcast timeout", broadcast, context.DeadlineExceeded, "", broadcast, "", false},
+	}
+	def := dep.ChainDef{Name: "ETH"}
+	for _, c := range cases {
+		order := &model.TenantWithdraw{Status: c.from, TxHash: "0xabc", RawTx: "f86b"}
+
+		event, err := sendWithdraw(context.Background(), stubGateway{err: c.err, receipt: c.receipt}, def, order)
+		if order.Status != c.status || event != c.event {
+			t.Errorf("%s: status %q event %q, want %q %q", c.name, order.Status, event, c.status, c.event)
+		}
+		// 只有节点明确拒绝时才释放 nonce
+		if got := dep.IsRejected(err); got != c.releasing {
+			t.Errorf("%s: release nonce %v, want %v", c.name, got, c.releasing)
+		}
+	}
+}
+
+func TestSameWithdraw(t *testing.T) {
+	amount := decimal.NewFromInt(100)
+	order := &model.TenantWithdraw{Chain: "ETH", TenantAddressID: 7, Token: "", ToAddress: "0xto", Amount: amount}
+	req := request.WithdrawCreateRequest{RequestID: "r1", Chain: "ETH", To: "0xto", Amount: "100"}
+	if !sameWithdraw(order, "ETH", 7, req, amount) {
+		t.Fatal("identical replay should match")
+	}
+	if sameWithdraw(order, "ETH", 8, req, amount) {
+		t.Fatal("different source address should conflict")
+	}
+	if sameWithdraw(order, "ETH", 7, req, decimal.NewFromInt(101)) {
+		t.Fatal("different amount should conflict")
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/reguluswee/walletus/cmd/modscanner/scanner"
+	"github.com/reguluswee/walletus/common/system"
+)
+
+const usage = `usage:
+  modscanner [run]
+  modscanner backfill -chain ETH -from 21000000 [-to 21001000]
+  modscanner rescan-block -chain ETH -height 21000000
+  modscanner rescan-address -chain ETH -address 0x... [-from 21000000] [-to 21001000]`
+
+// runCommand 执行一次性的补扫命令，入账按 (chain, tx_hash, log_index) 去重，可重复执行
+func runCommand(name string, args []string) error {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	chain := fs.String("chain", "", "chain name, e.g. ETH, TRON, SOLANA")
+	from := fs.Uint64("from", 0, "start height (slot on Solana), inclusive")
+	to := fs.Uint64("to", 0, "end height, inclusive; 0 means current head minus tx delay")
+	height := fs.Uint64("height", 0, "block height to rescan")
+	address := fs.String("address", "", "tenant address to rescan")
+	fs.Usage = func() { fmt.Fprintln(fs.Output(), usage) }
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *chain == "" {
+		return errors.New("-chain is required\n" + usage)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	db := system.GetDb()
+	if db == nil {
+		return errors.New("database not initialized, check allStart in config")
+	}
+
+	var n int
+	var err error
+	switch name {
+	case "backfill":
+		if *from == 0 {
+			return errors.New("-from is required\n" + usage)
+		}
+		if *to > 0 && *to < *from {
+			return errors.New("-to must not be less than -from")
+		}
+		n, err = scanner.Backfill(ctx, db, *chain, *from, *to)
+	case "rescan-block":
+		if *height == 0 {
+			return errors.New("-height is required\n" + usage)
+		}
+		n, err = scanner.Backfill(ctx, db, *chain, *height, *height)
+	case "rescan-address":
+		if *address == "" {
+			return errors.New("-address is required\n" + usage)
+		}
+		n, err = scanner.RescanAddress(ctx, db, *chain, *address, *from, *to)
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		return fmt.Errorf("unknown command %q", name)
+	}
+	fmt.Printf("%s %s: %d deposits found\n", name, *chain, n)
+	return err
+}
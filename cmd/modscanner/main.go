@@ -1,7 +1,86 @@
 package main
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/reguluswee/walletus/cmd/modscanner/scanner"
+	"github.com/reguluswee/walletus/common/chain/dep"
+	"github.com/reguluswee/walletus/common/config"
+	"github.com/reguluswee/walletus/common/log"
+	"github.com/reguluswee/walletus/common/system"
+)
 
 func main() {
-	fmt.Println("hello scanner")
+	name, args := "run", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if name == "run" {
+		run()
+		return
+	}
+	if err := runCommand(name, args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run 常驻扫描全部已配置的链，直到收到退出信号
+func run() {
+	fmt.Println("starting scanner...")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	db := system.GetDb()
+	if db == nil {
+		log.Error("database not initialized, check allStart in config")
+		os.Exit(1)
+	}
+
+	var wg sync.WaitGroup
+
+	// 每条配置了 RPC 的 EVM 链启动一个区块扫描
+	for _, def := range dep.GetSupportedEVMs() {
+		cc := config.GetRpcConfig(def.Name)
+		if cc == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(def dep.ChainDef, cc *config.ChainConfig) {
+			defer wg.Done()
+			scanner.RunEVM(ctx, db, def, cc)
+		}(def, cc)
+	}
+
+	if cc := config.GetRpcConfig(dep.GetSupportedTron().Name); cc != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			scanner.RunTron(ctx, db, cc)
+		}()
+	}
+
+	if cc := config.GetRpcConfig(dep.GetSupportedSol().Name); cc != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			scanner.RunSolana(ctx, db, cc)
+		}()
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	sig := <-sigChan
+	log.Info("Received signal:", sig)
+
+	cancel()
+	wg.Wait()
+	log.Info("Scanner shutdown complete")
 }
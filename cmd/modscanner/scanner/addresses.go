@@ -0,0 +1,97 @@
+package scanner
+
+import (
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// addressRef 命中的租户地址
+type addressRef struct {
+	TenantID  uint64
+	AddressID uint64
+	Address   string
+}
+
+// AddressBook 某条链上全部租户地址的内存索引
+// Refresh 只增量加载新创建的地址，每轮扫描前调用即可跟上新地址
+type AddressBook struct {
+	mu     sync.RWMutex
+	chain  string
+	fold   bool   // EVM 地址不区分大小写
+	only   string // 非空时只加载该地址，用于按地址重扫
+	lastID uint64
+	addrs  map[string]addressRef
+}
+
+func NewAddressBook(chain string, fold bool) *AddressBook {
+	return &AddressBook{
+		chain: chain,
+		fold:  fold,
+		addrs: make(map[string]addressRef),
+	}
+}
+
+// Only 限定只加载一个地址，需在首次 Refresh 前调用
+func (b *AddressBook) Only(address string) *AddressBook {
+	b.only = address
+	return b
+}
+
+func (b *AddressBook) Refresh(db *gorm.DB) error {
+	b.mu.RLock()
+	lastID := b.lastID
+	b.mu.RUnlock()
+
+	var rows []struct {
+		ID         uint64
+		TenantID   uint64
+		AddressVal string
+	}
+	tx := db.Table("tenant_address ta").
+		Joins("JOIN tenant_chain tc ON ta.tenant_chain_id = tc.id").
+		Where("tc.chain = ? and ta.id > ?", b.chain, lastID)
+	if b.only != "" {
+		tx = tx.Where("ta.address_val = ?", b.only)
+	}
+	err := tx.Order("ta.id").
+		Select("ta.id, ta.tenant_id, ta.address_val").
+		Scan(&rows).Error
+	if err != nil || len(rows) == 0 {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, r := range rows {
+		b.addrs[b.key(r.AddressVal)] = addressRef{TenantID: r.TenantID, AddressID: r.ID, Address: r.AddressVal}
+		if r.ID > b.lastID {
+			b.lastID = r.ID
+		}
+	}
+	return nil
+}
+
+func (b *AddressBook) Lookup(addr string) (addressRef, bool) {
+	if addr == "" {
+		return addressRef{}, false
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	ref, ok := b.addrs[b.key(addr)]
+	return ref, ok
+}
+
+func (b *AddressBook) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.addrs)
+}
+
+func (b *AddressBook) key(addr string) string {
+	if b.fold {
+		return strings.ToLower(addr)
+	}
+	return addr
+}
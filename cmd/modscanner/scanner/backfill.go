@@ -0,0 +1,168 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/reguluswee/walletus/common/chain/dep"
+	"github.com/reguluswee/walletus/common/chain/evm"
+	"github.com/reguluswee/walletus/common/chain/solana"
+	"github.com/reguluswee/walletus/common/chain/tron"
+	"github.com/reguluswee/walletus/common/config"
+	"github.com/reguluswee/walletus/common/log"
+	"gorm.io/gorm"
+)
+
+// addressScanner 能按地址索引查询历史的扫描器，按地址重扫时不必逐块读取
+type addressScanner interface {
+	ScanAddress(ctx context.Context, address string, from, to uint64) (*scanResult, error)
+}
+
+// Backfill 用链上全部扫描器重扫 [from, to]，to 为 0 表示扫到当前目标高度，返回找到的入账数
+// 只写入入账，不记录区块、不移动检查点，可与常驻扫描同时运行；入账按 (chain, tx_hash, log_index) 去重，重复执行是安全的
+// 新发现的入账与常驻扫描的一样由 Confirmer 推进确认并通知租户
+func Backfill(ctx context.Context, db *gorm.DB, chain string, from, to uint64) (int, error) {
+	scanners, cc, err := newChainScanners(db, chain, "")
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, s := range scanners {
+		end, err := scanTarget(ctx, s, cc, to)
+		if err != nil {
+			return total, err
+		}
+		n, err := backfillRange(ctx, db, s, from, end, uint64(cc.GetRangeRound()))
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// RescanAddress 重扫一个租户地址的历史入账，from 为 0 表示从头，to 为 0 表示到当前目标高度
+// 有地址索引的扫描器（EVM 代币日志、Solana 交易签名）直接查询该地址的历史；
+// 没有索引的（EVM 原生币、TRON 区块）只能逐块扫描，必须指定 from，否则跳过并提示
+func RescanAddress(ctx context.Context, db *gorm.DB, chain, address string, from, to uint64) (int, error) {
+	scanners, cc, err := newChainScanners(db, chain, address)
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, s := range scanners {
+		end, err := scanTarget(ctx, s, cc, to)
+		if err != nil {
+			return total, err
+		}
+		if as, ok := s.(addressScanner); ok {
+			res, err := as.ScanAddress(ctx, address, from, end)
+			if err != nil {
+				return total, err
+			}
+			if err := saveDeposits(db, res.Deposits); err != nil {
+				return total, err
+			}
+			total += len(res.Deposits)
+			log.Info("[backfill] ", chain, " ", s.Kind(), " ", address, " found ", len(res.Deposits), " deposits up to ", end)
+			continue
+		}
+		if from == 0 {
+			log.Info("[backfill] ", chain, " ", s.Kind(), " has no address index, skipped; pass a start height to scan it block by block")
+			continue
+		}
+		n, err := backfillRange(ctx, db, s, from, end, uint64(cc.GetRangeRound()))
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// backfillRange 按 step 分段扫描 [from, to] 并写入入账
+func backfillRange(ctx context.Context, db *gorm.DB, s rangeScanner, from, to, step uint64) (int, error) {
+	total := 0
+	for start := from; start <= to; start += step {
+		if ctx.Err() != nil {
+			return total, ctx.Err()
+		}
+		end := min(start+step-1, to)
+		res, err := s.ScanRange(ctx, start, end)
+		if err != nil {
+			return total, fmt.Errorf("%s %s %d-%d: %w", s.Chain(), s.Kind(), start, end, err)
+		}
+		if err := saveDeposits(db, res.Deposits); err != nil {
+			return total, err
+		}
+		total += len(res.Deposits)
+		log.Info("[backfill] ", s.Chain(), " ", s.Kind(), " ", start, "-", end, " found ", len(res.Deposits), " deposits")
+	}
+	return total, nil
+}
+
+// scanTarget to 为 0 时取链头减去 TxDelay，与常驻扫描的目标高度一致
+func scanTarget(ctx context.Context, s rangeScanner, cc *config.ChainConfig, to uint64) (uint64, error) {
+	if to > 0 {
+		return to, nil
+	}
+	head, err := s.Head(ctx)
+	if err != nil {
+		return 0, err
+	}
+	lag := uint64(cc.GetTxDelay())
+	if head <= lag {
+		return 0, fmt.Errorf("%s head %d below tx delay", s.Chain(), head)
+	}
+	return head - lag, nil
+}
+
+// newChainScanners 创建链上全部扫描器，共用一份地址索引；address 非空时只匹配该地址，且必须是已登记的租户地址
+func newChainScanners(db *gorm.DB, chain, address string) ([]rangeScanner, *config.ChainConfig, error) {
+	cc := config.GetRpcConfig(chain)
+	if cc == nil {
+		return nil, nil, fmt.Errorf("chain %s not configured", chain)
+	}
+
+	var book *AddressBook
+	var scanners []rangeScanner
+	switch {
+	case chain == dep.GetSupportedTron().Name:
+		def := dep.GetSupportedTron()
+		book = NewAddressBook(def.Name, false).Only(address)
+		scanners = append(scanners, NewTronScanner(db, def, tron.NewTRXClient(), book, cc))
+	case chain == dep.GetSupportedSol().Name:
+		book = NewAddressBook(chain, false).Only(address)
+		scanners = append(scanners, NewSolanaScanner(db, solana.NewSOLClient(), book, cc, nil))
+	default:
+		def, ok := findEVM(chain)
+		if !ok {
+			return nil, nil, fmt.Errorf("chain %s not supported", chain)
+		}
+		client := evm.NewEVMClient(def)
+		book = NewAddressBook(def.Name, true).Only(address)
+		scanners = append(scanners, NewEVMBlockScanner(db, def, client, book, cc))
+		if len(cc.Tokens) > 0 {
+			scanners = append(scanners, NewEVMLogScanner(db, def, client, book, cc))
+		}
+	}
+
+	if address != "" {
+		if err := book.Refresh(db); err != nil {
+			return nil, nil, err
+		}
+		if book.Len() == 0 {
+			return nil, nil, fmt.Errorf("address %s is not a tenant address on %s", address, chain)
+		}
+	}
+	return scanners, cc, nil
+}
+
+func findEVM(chain string) (dep.ChainDef, bool) {
+	for _, def := range dep.GetSupportedEVMs() {
+		if def.Name == chain {
+			return def, true
+		}
+	}
+	return dep.ChainDef{}, false
+}
@@ -0,0 +1,159 @@
+package scanner
+
+import (
+	"context"
+	"time"
+
+	"github.com/reguluswee/walletus/common/chain/dep"
+	"github.com/reguluswee/walletus/common/config"
+	"github.com/reguluswee/walletus/common/log"
+	"github.com/reguluswee/walletus/common/model"
+	"github.com/reguluswee/walletus/common/webhook"
+	"gorm.io/gorm"
+)
+
+const (
+	// 每轮最多推进的入账数，按 id 顺序处理，先到的先确认
+	confirmBatch = 500
+	// 要求最终确认时的模式
+	finalityFinalized = "finalized"
+)
+
+// headSource 确认计算需要的链头高度
+type headSource interface {
+	Head(ctx context.Context) (uint64, error)
+	FinalizedHead(ctx context.Context) (uint64, error)
+}
+
+// Confirmer 随链头推进 seen / confirming 入账的确认数，达到要求后标记为 confirmed 并通知租户
+// 配置了 wake 时随新区块推送推进，并优先使用推送的链头
+type Confirmer struct {
+	db    *gorm.DB
+	chain string
+	heads headSource
+	cc    *config.ChainConfig
+	wake  *wakeup
+}
+
+func NewConfirmer(db *gorm.DB, chain string, heads headSource, cc *config.ChainConfig, wake *wakeup) *Confirmer {
+	return &Confirmer{db: db, chain: chain, heads: heads, cc: cc, wake: wake}
+}
+
+// Run 持续推进确认直到 ctx 取消
+func (c *Confirmer) Run(ctx context.Context) {
+	log.Info("[scanner] start ", c.chain, " confirmer")
+	for {
+		if err := c.round(ctx); err != nil {
+			log.Error("[scanner] ", c.chain, " confirm round failed: ", err)
+		}
+		if !c.wake.wait(ctx, pollInterval) {
+			return
+		}
+	}
+}
+
+func (c *Confirmer) round(ctx context.Context) error {
+	var pending []model.TenantDeposit
+	err := c.db.Where("chain = ? and status in ?", c.chain, []string{model.DepositStatusSeen, model.DepositStatusConfirming}).
+		Order("id").Limit(confirmBatch).Find(&pending).Error
+	if err != nil || len(pending) == 0 {
+		return err
+	}
+
+	head, ok := c.wake.Head()
+	if !ok {
+		if head, err = c.heads.Head(ctx); err != nil {
+			return err
+		}
+	}
+	// 只有存在要求最终确认的入账时才读取 finalized 高度
+	var finalized uint64
+	for _, d := range pending {
+		if requiredConsistency(c.cc, d.Token).Mode == finalityFinalized {
+			if finalized, err = c.heads.FinalizedHead(ctx); err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	confirmed := 0
+	for i := range pending {
+		d := &pending[i]
+		cs := requiredConsistency(c.cc, d.Token)
+		status, n := confirmProgress(d.BlockNumber, head, finalized, cs)
+		if status == d.Status && n == d.Confirmations {
+			continue
+		}
+		ok, err := c.advance(d, status, n, cs.MinConfirmations)
+		if err != nil {
+			return err
+		}
+		if ok && status == model.DepositStatusConfirmed {
+			confirmed++
+		}
+	}
+	if confirmed > 0 {
+		log.Info("[scanner] ", c.chain, " confirmed ", confirmed, " deposits at head ", head)
+	}
+	return nil
+}
+
+// advance 以原状态为条件更新，期间被链重组回滚的入账不会被改回
+// 首次进入后续区块时记录 deposit.seen，确认时记录 deposit.confirmed，与状态在同一事务中写入
+func (c *Confirmer) advance(d *model.TenantDeposit, status string, n, required int) (bool, error) {
+	ok := false
+	err := c.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		updates := map[string]interface{}{"status": status, "confirmations": n, "required": required, "update_time": now}
+		if status == model.DepositStatusConfirmed {
+			updates["confirm_time"] = now
+		}
+		res := tx.Model(&model.TenantDeposit{}).Where("id = ? and status = ?", d.ID, d.Status).Updates(updates)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		ok = true
+		seen := d.Status == model.DepositStatusSeen
+		d.Status, d.Confirmations, d.Required, d.UpdateTime = status, n, required, now
+		if seen {
+			if err := webhook.Emit(tx, d.TenantID, model.EventDepositSeen, d.ID, d); err != nil {
+				return err
+			}
+		}
+		if status != model.DepositStatusConfirmed {
+			return nil
+		}
+		d.ConfirmTime = &now
+		return webhook.Emit(tx, d.TenantID, model.EventDepositConfirmed, d.ID, d)
+	})
+	return ok, err
+}
+
+// requiredConsistency 代币的确认要求，Mode 为 finalized 时等待最终确认，否则按 MinConfirmations 计数
+func requiredConsistency(cc *config.ChainConfig, token string) dep.Consistency {
+	c := cc.GetConfirm(token)
+	if c.Finality == finalityFinalized {
+		return dep.Consistency{Mode: finalityFinalized}
+	}
+	return dep.Consistency{MinConfirmations: max(c.Blocks, 1)}
+}
+
+// confirmProgress 计算入账在当前链头下的状态与确认数，入账所在区块本身算 1 个确认
+func confirmProgress(block, head, finalized uint64, cs dep.Consistency) (string, int) {
+	n := 0
+	if head >= block {
+		n = int(head - block + 1)
+	}
+	if cs.Mode == finalityFinalized {
+		if finalized >= block {
+			return model.DepositStatusConfirmed, n
+		}
+	} else if n >= cs.MinConfirmations {
+		return model.DepositStatusConfirmed, n
+	}
+	if n == 0 {
+		return model.DepositStatusSeen, 0
+	}
+	return model.DepositStatusConfirming, n
+}
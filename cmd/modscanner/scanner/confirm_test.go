@@ -0,0 +1,41 @@
+package scanner
+
+import (
+	"testing"
+
+	"github.com/reguluswee/walletus/common/config"
+	"github.com/reguluswee/walletus/common/model"
+)
+
+func TestConfirmProgress(t *testing.T) {
+	cc := &config.ChainConfig{Confirms: []config.ConfirmConfig{
+		{Blocks: 12},
+		{Token: "USDT", Finality: "finalized"},
+	}}
+
+	cases := []struct {
+		token                  string
+		block, head, finalized uint64
+		status                 string
+		n                      int
+	}{
+		{"", 100, 99, 0, model.DepositStatusSeen, 0},
+		{"", 100, 100, 0, model.DepositStatusConfirming, 1},
+		{"", 100, 110, 0, model.DepositStatusConfirming, 11},
+		{"", 100, 111, 0, model.DepositStatusConfirmed, 12},
+		{"USDC", 100, 111, 0, model.DepositStatusConfirmed, 12},
+		{"USDT", 100, 200, 99, model.DepositStatusConfirming, 101},
+		{"USDT", 100, 200, 100, model.DepositStatusConfirmed, 101},
+	}
+	for _, c := range cases {
+		status, n := confirmProgress(c.block, c.head, c.finalized, requiredConsistency(cc, c.token))
+		if status != c.status || n != c.n {
+			t.Errorf("%q block %d head %d finalized %d: got %s %d, want %s %d", c.token, c.block, c.head, c.finalized, status, n, c.status, c.n)
+		}
+	}
+
+	// 未配置时默认 1 个确认
+	if cs := requiredConsistency(&config.ChainConfig{}, ""); cs.MinConfirmations != 1 || cs.Mode != "" {
+		t.Fatalf("default consistency = %+v", cs)
+	}
+}
@@ -0,0 +1,52 @@
+package scanner
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/reguluswee/walletus/common/model"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// newDeposit 由命中的租户地址生成入账记录
+func newDeposit(chain string, ref addressRef, token, from string, amount *big.Int, txHash string, logIndex int, blockNumber uint64, blockHash string) model.TenantDeposit {
+	now := time.Now()
+	return model.TenantDeposit{
+		TenantID:        ref.TenantID,
+		TenantAddressID: ref.AddressID,
+		Chain:           chain,
+		Token:           token,
+		FromAddress:     from,
+		ToAddress:       ref.Address,
+		Amount:          decimal.NewFromBigInt(amount, 0),
+		TxHash:          txHash,
+		LogIndex:        logIndex,
+		BlockNumber:     blockNumber,
+		BlockHash:       blockHash,
+		Status:          model.DepositStatusSeen,
+		AddTime:         now,
+		UpdateTime:      now,
+	}
+}
+
+// revivedIfReverted 已存在的入账只在其被重组回滚过时，用规范链上的新区块恢复；其余情况保持不变
+// MySQL 按顺序求值，status 必须最后更新
+var revivedIfReverted = clause.Set{
+	{Column: clause.Column{Name: "block_number"}, Value: gorm.Expr("IF(status = ?, VALUES(block_number), block_number)", model.DepositStatusReverted)},
+	{Column: clause.Column{Name: "block_hash"}, Value: gorm.Expr("IF(status = ?, VALUES(block_hash), block_hash)", model.DepositStatusReverted)},
+	{Column: clause.Column{Name: "confirmations"}, Value: gorm.Expr("IF(status = ?, VALUES(confirmations), confirmations)", model.DepositStatusReverted)},
+	{Column: clause.Column{Name: "required"}, Value: gorm.Expr("IF(status = ?, VALUES(required), required)", model.DepositStatusReverted)},
+	{Column: clause.Column{Name: "confirm_time"}, Value: gorm.Expr("IF(status = ?, VALUES(confirm_time), confirm_time)", model.DepositStatusReverted)},
+	{Column: clause.Column{Name: "update_time"}, Value: gorm.Expr("IF(status = ?, VALUES(update_time), update_time)", model.DepositStatusReverted)},
+	{Column: clause.Column{Name: "status"}, Value: gorm.Expr("IF(status = ?, VALUES(status), status)", model.DepositStatusReverted)},
+}
+
+// saveDeposits 写入入账，(chain, tx_hash, log_index) 已存在时不重复记录，重复扫描同一区间是安全的
+func saveDeposits(db *gorm.DB, deposits []model.TenantDeposit) error {
+	if len(deposits) == 0 {
+		return nil
+	}
+	return db.Clauses(clause.OnConflict{DoUpdates: revivedIfReverted}).CreateInBatches(deposits, 200).Error
+}
@@ -0,0 +1,147 @@
+package scanner
+
+import (
+	"context"
+	"sync"
+
+	"github.com/reguluswee/walletus/common/chain/dep"
+	"github.com/reguluswee/walletus/common/chain/evm"
+	"github.com/reguluswee/walletus/common/config"
+	"github.com/reguluswee/walletus/common/model"
+	"golang.org/x/sync/errgroup"
+	"gorm.io/gorm"
+)
+
+const KindBlock = "block"
+
+// EVMBlockScanner 逐块读取完整交易，识别转入租户地址的原生币
+type EVMBlockScanner struct {
+	db       *gorm.DB
+	chain    dep.ChainDef
+	client   *evm.EVMClient
+	book     *AddressBook
+	parallel int
+}
+
+func NewEVMBlockScanner(db *gorm.DB, chain dep.ChainDef, client *evm.EVMClient, book *AddressBook, cc *config.ChainConfig) *EVMBlockScanner {
+	return &EVMBlockScanner{
+		db:       db,
+		chain:    chain,
+		client:   client,
+		book:     book,
+		parallel: cc.GetSlotParallel(),
+	}
+}
+
+func (s *EVMBlockScanner) Chain() string { return s.chain.Name }
+
+func (s *EVMBlockScanner) Kind() string { return KindBlock }
+
+func (s *EVMBlockScanner) Head(ctx context.Context) (uint64, error) {
+	return s.client.BlockNumber(ctx, s.chain.Name)
+}
+
+// FinalizedHead finalized 标签对应的区块高度
+func (s *EVMBlockScanner) FinalizedHead(ctx context.Context) (uint64, error) {
+	anchor, err := s.client.Anchor(ctx, s.chain.Name, dep.Consistency{Mode: finalityFinalized})
+	if err != nil {
+		return 0, err
+	}
+	return anchor.Height, nil
+}
+
+// ScanRange 以 SlotParallel 并发读取 [from, to] 的区块
+func (s *EVMBlockScanner) ScanRange(ctx context.Context, from, to uint64) (*scanResult, error) {
+	if err := s.book.Refresh(s.db); err != nil {
+		return nil, err
+	}
+
+	blocks := make([]*evm.Block, to-from+1)
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(s.parallel)
+	for h := from; h <= to; h++ {
+		h := h
+		g.Go(func() error {
+			b, err := s.client.BlockByNumber(gctx, s.chain.Name, h)
+			if err != nil {
+				return err
+			}
+			blocks[h-from] = b
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	res := &scanResult{}
+	for _, b := range blocks {
+		res.Deposits = append(res.Deposits, s.matchBlock(b)...)
+		res.Blocks = append(res.Blocks, model.ScanBlock{Height: b.Number, Hash: b.Hash, ParentHash: b.ParentHash})
+	}
+	return res, nil
+}
+
+func (s *EVMBlockScanner) BlockHash(ctx context.Context, height uint64) (string, error) {
+	return s.client.BlockHash(ctx, s.chain.Name, height)
+}
+
+func (s *EVMBlockScanner) matchBlock(b *evm.Block) []model.TenantDeposit {
+	var out []model.TenantDeposit
+	for _, tx := range b.Transactions {
+		if tx.Value.Sign() <= 0 {
+			continue
+		}
+		ref, ok := s.book.Lookup(tx.To)
+		if !ok {
+			continue
+		}
+		out = append(out, newDeposit(s.chain.Name, ref, "", tx.From, tx.Value, tx.Hash, model.NativeLogIndex, b.Number, b.Hash))
+	}
+	return out
+}
+
+// RunEVM 启动一条 EVM 链的原生币区块扫描与入账确认，配置了代币白名单时同时启动 Transfer 日志扫描，直到 ctx 取消
+// 两个扫描器各自维护检查点，共用同一份地址索引；配置了 WsRpc 时订阅 newHeads 与 logs 推送唤醒扫描
+func RunEVM(ctx context.Context, db *gorm.DB, chain dep.ChainDef, cc *config.ChainConfig) {
+	client := evm.NewEVMClient(chain)
+	book := NewAddressBook(chain.Name, true)
+	blocks := NewEVMBlockScanner(db, chain, client, book, cc)
+
+	var blockWake, logWake, confirmWake *wakeup
+	var wg sync.WaitGroup
+	if cc.WsRpc != "" {
+		blockWake, logWake, confirmWake = newWakeup(), newWakeup(), newWakeup()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			keepSubscribed(ctx, chain.Name+" newHeads", true, func(ctx context.Context, out chan<- uint64) error {
+				return evm.SubscribeNewHeads(ctx, cc.WsRpc, out)
+			}, blockWake, logWake, confirmWake)
+		}()
+		if len(cc.Tokens) > 0 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				keepSubscribed(ctx, chain.Name+" logs", false, func(ctx context.Context, out chan<- uint64) error {
+					return evm.SubscribeTransferLogs(ctx, cc.WsRpc, cc.Tokens, out)
+				}, logWake)
+			}()
+		}
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		NewConfirmer(db, chain.Name, blocks, cc, confirmWake).Run(ctx)
+	}()
+	if len(cc.Tokens) > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			newRunner(db, NewEVMLogScanner(db, chain, client, book, cc), cc, logWake).Run(ctx)
+		}()
+	}
+	newRunner(db, blocks, cc, blockWake).Run(ctx)
+	wg.Wait()
+}
@@ -0,0 +1,111 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/reguluswee/walletus/common/chain/dep"
+	"github.com/reguluswee/walletus/common/chain/evm"
+	"github.com/reguluswee/walletus/common/config"
+	"github.com/reguluswee/walletus/common/log"
+	"github.com/reguluswee/walletus/common/model"
+	"gorm.io/gorm"
+)
+
+const KindERC20 = "erc20"
+
+// EVMLogScanner 通过 eth_getLogs 读取白名单代币的 Transfer 事件，按 to 匹配租户地址
+type EVMLogScanner struct {
+	db     *gorm.DB
+	chain  dep.ChainDef
+	client *evm.EVMClient
+	book   *AddressBook
+	tokens []string
+	names  map[string]string // 小写合约地址 -> 配置中的写法
+	max    uint64
+	span   uint64 // 当前单次查询的区块数，节点报超限时减半，成功后逐步恢复
+}
+
+func NewEVMLogScanner(db *gorm.DB, chain dep.ChainDef, client *evm.EVMClient, book *AddressBook, cc *config.ChainConfig) *EVMLogScanner {
+	s := &EVMLogScanner{
+		db:     db,
+		chain:  chain,
+		client: client,
+		book:   book,
+		tokens: cc.Tokens,
+		names:  make(map[string]string, len(cc.Tokens)),
+		max:    uint64(cc.GetRangeRound()),
+	}
+	for _, t := range cc.Tokens {
+		s.names[strings.ToLower(t)] = t
+	}
+	s.span = s.max
+	return s
+}
+
+func (s *EVMLogScanner) Chain() string { return s.chain.Name }
+
+func (s *EVMLogScanner) Kind() string { return KindERC20 }
+
+func (s *EVMLogScanner) Head(ctx context.Context) (uint64, error) {
+	return s.client.BlockNumber(ctx, s.chain.Name)
+}
+
+// ScanRange 分段查询 [from, to]，入账所在区块由 runner 与区块扫描器的记录比对
+func (s *EVMLogScanner) ScanRange(ctx context.Context, from, to uint64) (*scanResult, error) {
+	return s.scanLogs(ctx, "", from, to)
+}
+
+// ScanAddress 只查询转入 address 的 Transfer，由节点按 topic 过滤，可直接覆盖整段历史
+func (s *EVMLogScanner) ScanAddress(ctx context.Context, address string, from, to uint64) (*scanResult, error) {
+	return s.scanLogs(ctx, address, from, to)
+}
+
+func (s *EVMLogScanner) scanLogs(ctx context.Context, recipient string, from, to uint64) (*scanResult, error) {
+	if err := s.book.Refresh(s.db); err != nil {
+		return nil, err
+	}
+
+	res := &scanResult{}
+	for start := from; start <= to; {
+		end := start + s.span - 1
+		if end > to {
+			end = to
+		}
+		logs, err := s.client.TransferLogsTo(ctx, s.chain.Name, s.tokens, recipient, start, end)
+		if errors.Is(err, evm.ErrLogRangeTooLarge) && end > start {
+			s.span = (end - start + 1) / 2
+			log.Info("[scanner] ", s.chain.Name, " log range too large, shrink to ", s.span)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		res.Deposits = append(res.Deposits, s.matchLogs(logs)...)
+		start = end + 1
+		if s.span < s.max {
+			s.span = min(s.span*2, s.max)
+		}
+	}
+	return res, nil
+}
+
+func (s *EVMLogScanner) matchLogs(logs []evm.TransferLog) []model.TenantDeposit {
+	var out []model.TenantDeposit
+	for _, l := range logs {
+		if l.Amount.Sign() <= 0 {
+			continue
+		}
+		ref, ok := s.book.Lookup(l.To)
+		if !ok {
+			continue
+		}
+		token, ok := s.names[l.Token]
+		if !ok {
+			continue
+		}
+		out = append(out, newDeposit(s.chain.Name, ref, token, l.From, l.Amount, l.TxHash, int(l.LogIndex), l.BlockNumber, l.BlockHash))
+	}
+	return out
+}
@@ -0,0 +1,33 @@
+package scanner
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/reguluswee/walletus/common/chain/dep"
+	"github.com/reguluswee/walletus/common/chain/evm"
+	"github.com/reguluswee/walletus/common/config"
+)
+
+func TestEVMMatchLogs(t *testing.T) {
+	book := NewAddressBook("BSC", true)
+	book.addrs[book.key("0xaBc0000000000000000000000000000000000001")] = addressRef{TenantID: 7, AddressID: 11, Address: "0xaBc0000000000000000000000000000000000001"}
+
+	usdt := "0x55d398326f99059fF775485246999027B3197955"
+	s := NewEVMLogScanner(nil, dep.ChainDef{Name: "BSC"}, nil, book, &config.ChainConfig{Tokens: []string{usdt}, RangeRound: 100})
+
+	logs := []evm.TransferLog{
+		{Token: "0x55d398326f99059ff775485246999027b3197955", From: "0xf", To: "0xabc0000000000000000000000000000000000001", Amount: big.NewInt(100), TxHash: "0x1", LogIndex: 4, BlockNumber: 9, BlockHash: "0xb"},
+		{Token: "0x55d398326f99059ff775485246999027b3197955", From: "0xf", To: "0xdef0000000000000000000000000000000000002", Amount: big.NewInt(100), TxHash: "0x2"},
+		{Token: "0x0000000000000000000000000000000000000bad", From: "0xf", To: "0xabc0000000000000000000000000000000000001", Amount: big.NewInt(100), TxHash: "0x3"},
+		{Token: "0x55d398326f99059ff775485246999027b3197955", From: "0xf", To: "0xabc0000000000000000000000000000000000001", Amount: big.NewInt(0), TxHash: "0x4"},
+	}
+	got := s.matchLogs(logs)
+	if len(got) != 1 {
+		t.Fatalf("got %d deposits, want 1", len(got))
+	}
+	d := got[0]
+	if d.Token != usdt || d.LogIndex != 4 || d.TxHash != "0x1" || d.Amount.String() != "100" || d.BlockNumber != 9 {
+		t.Fatalf("unexpected deposit %+v", d)
+	}
+}
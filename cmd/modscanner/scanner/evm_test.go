@@ -0,0 +1,39 @@
+package scanner
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/reguluswee/walletus/common/chain/dep"
+	"github.com/reguluswee/walletus/common/chain/evm"
+	"github.com/reguluswee/walletus/common/model"
+)
+
+func TestEVMMatchBlock(t *testing.T) {
+	book := NewAddressBook("BSC", true)
+	book.addrs[book.key("0xAbC0000000000000000000000000000000000001")] = addressRef{TenantID: 7, AddressID: 11, Address: "0xAbC0000000000000000000000000000000000001"}
+
+	s := &EVMBlockScanner{chain: dep.ChainDef{Name: "BSC"}, book: book}
+	b := &evm.Block{
+		Number: 100,
+		Hash:   "0xblock",
+		Transactions: []evm.Transaction{
+			{Hash: "0x1", From: "0xf", To: "0xabc0000000000000000000000000000000000001", Value: big.NewInt(5)},
+			{Hash: "0x2", From: "0xf", To: "0xabc0000000000000000000000000000000000001", Value: big.NewInt(0)},
+			{Hash: "0x3", From: "0xf", To: "0xdef0000000000000000000000000000000000002", Value: big.NewInt(9)},
+			{Hash: "0x4", From: "0xf", To: "", Value: big.NewInt(9)},
+		},
+	}
+
+	got := s.matchBlock(b)
+	if len(got) != 1 {
+		t.Fatalf("got %d deposits, want 1", len(got))
+	}
+	d := got[0]
+	if d.TenantID != 7 || d.TenantAddressID != 11 || d.TxHash != "0x1" || d.LogIndex != model.NativeLogIndex {
+		t.Fatalf("unexpected deposit %+v", d)
+	}
+	if d.ToAddress != "0xAbC0000000000000000000000000000000000001" || d.Amount.String() != "5" || d.BlockHash != "0xblock" {
+		t.Fatalf("unexpected deposit %+v", d)
+	}
+}
@@ -0,0 +1,164 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/reguluswee/walletus/common/model"
+	"github.com/reguluswee/walletus/common/webhook"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// reorgWindow 保留最近多少个区块的哈希，超过该深度的重组无法自动处理
+const reorgWindow = 256
+
+// reorgError 新区块的父哈希与已记录的区块不一致
+type reorgError struct {
+	Height   uint64
+	Recorded string
+	Parent   string
+}
+
+func (e *reorgError) Error() string {
+	return fmt.Sprintf("reorg detected at %d: recorded %s, new parent %s", e.Height, e.Recorded, e.Parent)
+}
+
+func sameHash(a, b string) bool {
+	return strings.EqualFold(strings.TrimPrefix(a, "0x"), strings.TrimPrefix(b, "0x"))
+}
+
+// checkContinuity 校验区间首块与已记录的前一块相连，且区间内逐块相连
+func checkContinuity(tx *gorm.DB, chain string, blocks []model.ScanBlock) error {
+	first := blocks[0]
+	var prev model.ScanBlock
+	if err := tx.Where("chain = ? and height = ?", chain, first.Height-1).Limit(1).Find(&prev).Error; err != nil {
+		return err
+	}
+	if prev.Hash != "" && !sameHash(prev.Hash, first.ParentHash) {
+		return &reorgError{Height: prev.Height, Recorded: prev.Hash, Parent: first.ParentHash}
+	}
+	for i := 1; i < len(blocks); i++ {
+		if !sameHash(blocks[i].ParentHash, blocks[i-1].Hash) {
+			// 读取期间链发生了变化，下一轮重新读取
+			return fmt.Errorf("blocks %d and %d are not linked", blocks[i-1].Height, blocks[i].Height)
+		}
+	}
+	return nil
+}
+
+// checkDepositBlocks 校验入账所在区块与区块扫描器记录的一致
+// 区块尚未记录时返回 errBlocksNotReady；早于记录窗口的区块已不会重组，直接放行
+func checkDepositBlocks(tx *gorm.DB, chain string, deposits []model.TenantDeposit) error {
+	if len(deposits) == 0 {
+		return nil
+	}
+	heights := make([]uint64, 0, len(deposits))
+	for _, d := range deposits {
+		heights = append(heights, d.BlockNumber)
+	}
+	var rows []model.ScanBlock
+	if err := tx.Where("chain = ? and height in ?", chain, heights).Find(&rows).Error; err != nil {
+		return err
+	}
+	recorded := make(map[uint64]string, len(rows))
+	for _, b := range rows {
+		recorded[b.Height] = b.Hash
+	}
+	var top uint64
+	if err := tx.Model(&model.ScanBlock{}).Where("chain = ?", chain).Select("COALESCE(MAX(height), 0)").Scan(&top).Error; err != nil {
+		return err
+	}
+
+	for _, d := range deposits {
+		hash, ok := recorded[d.BlockNumber]
+		if !ok {
+			if d.BlockNumber > top {
+				return errBlocksNotReady
+			}
+			continue
+		}
+		if !sameHash(hash, d.BlockHash) {
+			return errBlocksNotReady
+		}
+	}
+	return nil
+}
+
+// recordBlocks 记录区块哈希，并清理窗口之外的旧记录
+func recordBlocks(tx *gorm.DB, chain string, blocks []model.ScanBlock) error {
+	now := time.Now()
+	for i := range blocks {
+		blocks[i].Chain = chain
+		blocks[i].AddTime = now
+	}
+	if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).CreateInBatches(blocks, 200).Error; err != nil {
+		return err
+	}
+	last := blocks[len(blocks)-1].Height
+	if last <= reorgWindow {
+		return nil
+	}
+	return tx.Where("chain = ? and height < ?", chain, last-reorgWindow).Delete(&model.ScanBlock{}).Error
+}
+
+// findCommonAncestor 从 height 向下逐块比对已记录的哈希与规范链，返回第一个一致的高度
+func findCommonAncestor(ctx context.Context, db *gorm.DB, chain string, height uint64, hashAt func(context.Context, uint64) (string, error)) (uint64, error) {
+	var rows []model.ScanBlock
+	if err := db.Where("chain = ? and height <= ?", chain, height).Order("height DESC").Limit(reorgWindow).Find(&rows).Error; err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return height, nil
+	}
+	for _, b := range rows {
+		canonical, err := hashAt(ctx, b.Height)
+		if err != nil {
+			return 0, err
+		}
+		if sameHash(canonical, b.Hash) {
+			return b.Height, nil
+		}
+	}
+	// 整个窗口都已被替换，只能从窗口之前重扫
+	return rows[len(rows)-1].Height - 1, nil
+}
+
+// rewind 回退该链所有扫描器的检查点到 ancestor，祖先之后的入账标记为 reverted 并通知租户
+// 返回回滚的入账数
+func rewind(db *gorm.DB, chain string, ancestor uint64) (int, error) {
+	var reverted []model.TenantDeposit
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// 先更新检查点，与扫描结果提交时锁定检查点的顺序一致
+		err := tx.Model(&model.ScanCheckpoint{}).
+			Where("chain = ? and height > ?", chain, ancestor).
+			Updates(map[string]interface{}{"height": ancestor, "block_hash": "", "update_time": time.Now()}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("chain = ? and block_number > ? and status <> ?", chain, ancestor, model.DepositStatusReverted).
+			Find(&reverted).Error
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		for i := range reverted {
+			d := &reverted[i]
+			d.Status = model.DepositStatusReverted
+			d.UpdateTime = now
+			if err := tx.Model(d).Updates(map[string]interface{}{"status": d.Status, "update_time": now}).Error; err != nil {
+				return err
+			}
+			if err := webhook.Emit(tx, d.TenantID, model.EventDepositReverted, d.ID, d); err != nil {
+				return err
+			}
+		}
+
+		return tx.Where("chain = ? and height > ?", chain, ancestor).Delete(&model.ScanBlock{}).Error
+	})
+	return len(reverted), err
+}
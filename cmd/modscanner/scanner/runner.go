@@ -0,0 +1,222 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/reguluswee/walletus/common/config"
+	"github.com/reguluswee/walletus/common/log"
+	"github.com/reguluswee/walletus/common/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 追上链头后的轮询间隔
+const pollInterval = 3 * time.Second
+
+var (
+	// errCheckpointMoved 扫描期间检查点被回退（链重组），本轮结果作废
+	errCheckpointMoved = errors.New("checkpoint moved during scan")
+	// errBlocksNotReady 日志对应的区块尚未被区块扫描器记录，稍后再扫
+	errBlocksNotReady = errors.New("blocks not recorded yet")
+)
+
+// scanResult 一段区间的扫描结果
+// Blocks 由区块扫描器返回，写入前校验与已记录区块的父哈希连续性；
+// 不返回 Blocks 的扫描器（如日志扫描）改为校验入账所在区块的哈希与已记录的一致
+type scanResult struct {
+	Deposits []model.TenantDeposit
+	Blocks   []model.ScanBlock
+}
+
+// rangeScanner 按高度区间扫描的链
+// ScanRange 只读取和匹配，不写库；结果由 runner 在事务中与检查点一起提交
+type rangeScanner interface {
+	Chain() string
+	Kind() string
+	Head(ctx context.Context) (uint64, error)
+	ScanRange(ctx context.Context, from, to uint64) (*scanResult, error)
+}
+
+// reorgScanner 能读取规范链上指定高度区块哈希的扫描器，支持链重组回滚
+type reorgScanner interface {
+	BlockHash(ctx context.Context, height uint64) (string, error)
+}
+
+// runner 从检查点开始逐段扫描，始终落后链头 lag 个区块，每段最多 step 个区块
+// 配置了 wake 时由 WebSocket 推送唤醒，并优先使用推送的链头
+type runner struct {
+	db   *gorm.DB
+	s    rangeScanner
+	lag  uint64
+	step uint64
+	wake *wakeup
+}
+
+func newRunner(db *gorm.DB, s rangeScanner, cc *config.ChainConfig, wake *wakeup) *runner {
+	return &runner{
+		db:   db,
+		s:    s,
+		lag:  uint64(cc.GetTxDelay()),
+		step: uint64(cc.GetRangeRound()),
+		wake: wake,
+	}
+}
+
+// Run 持续扫描直到 ctx 取消
+func (r *runner) Run(ctx context.Context) {
+	log.Info("[scanner] start ", r.s.Chain(), " ", r.s.Kind())
+	for {
+		caughtUp, err := r.round(ctx)
+		var re *reorgError
+		switch {
+		case errors.As(err, &re):
+			r.handleReorg(ctx, re)
+		case errors.Is(err, errBlocksNotReady), errors.Is(err, errCheckpointMoved):
+			caughtUp = true
+		case err != nil:
+			log.Error("[scanner] ", r.s.Chain(), " ", r.s.Kind(), " round failed: ", err)
+		}
+		if !caughtUp && err == nil {
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+		if !r.wake.wait(ctx, pollInterval) {
+			return
+		}
+	}
+}
+
+// round 扫描一段区间，返回是否已追上目标高度
+func (r *runner) round(ctx context.Context) (bool, error) {
+	head, ok := r.wake.Head()
+	if !ok {
+		var err error
+		if head, err = r.s.Head(ctx); err != nil {
+			return true, err
+		}
+	}
+	if head <= r.lag {
+		return true, nil
+	}
+	target := head - r.lag
+
+	cp, ok, err := loadCheckpoint(r.db, r.s.Chain(), r.s.Kind())
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		// 首次启动从当前目标高度开始，历史区块通过补扫处理
+		_, err := initCheckpoint(r.db, r.s.Chain(), r.s.Kind(), target-1)
+		return true, err
+	}
+	from := cp.Height + 1
+	if from > target {
+		return true, nil
+	}
+	to := from + r.step - 1
+	if to > target {
+		to = target
+	}
+	res, err := r.s.ScanRange(ctx, from, to)
+	if err != nil {
+		return false, err
+	}
+	if err := r.commit(from, to, res); err != nil {
+		return false, err
+	}
+	if len(res.Deposits) > 0 {
+		log.Info("[scanner] ", r.s.Chain(), " ", r.s.Kind(), " found ", len(res.Deposits), " deposits in ", from, "-", to)
+	}
+	return to == target, nil
+}
+
+// commit 在一个事务中写入入账、区块记录并推进检查点
+// 先锁定检查点行：与链重组回滚互斥，检查点已被回退时本轮结果整体作废
+func (r *runner) commit(from, to uint64, res *scanResult) error {
+	chain, kind := r.s.Chain(), r.s.Kind()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var cp model.ScanCheckpoint
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("chain = ? and kind = ?", chain, kind).Limit(1).Find(&cp).Error
+		if err != nil {
+			return err
+		}
+		if cp.Chain == "" || cp.Height != from-1 {
+			return errCheckpointMoved
+		}
+
+		if len(res.Blocks) > 0 {
+			if err := checkContinuity(tx, chain, res.Blocks); err != nil {
+				return err
+			}
+			if err := recordBlocks(tx, chain, res.Blocks); err != nil {
+				return err
+			}
+		} else if err := checkDepositBlocks(tx, chain, res.Deposits); err != nil {
+			return err
+		}
+		if err := saveDeposits(tx, res.Deposits); err != nil {
+			return err
+		}
+
+		last := ""
+		if n := len(res.Blocks); n > 0 {
+			last = res.Blocks[n-1].Hash
+		}
+		return tx.Model(&model.ScanCheckpoint{}).
+			Where("chain = ? and kind = ?", chain, kind).
+			Updates(map[string]interface{}{"height": to, "block_hash": last, "update_time": time.Now()}).Error
+	})
+}
+
+// handleReorg 找到共同祖先后回滚，之后各扫描器从祖先的下一个区块重扫规范链
+func (r *runner) handleReorg(ctx context.Context, re *reorgError) {
+	rs, ok := r.s.(reorgScanner)
+	if !ok {
+		log.Error("[scanner] ", r.s.Chain(), " reorg detected but scanner cannot rewind: ", re)
+		return
+	}
+	log.Info("[scanner] ", r.s.Chain(), " ", re)
+	ancestor, err := findCommonAncestor(ctx, r.db, r.s.Chain(), re.Height, rs.BlockHash)
+	if err != nil {
+		log.Error("[scanner] ", r.s.Chain(), " find common ancestor failed: ", err)
+		return
+	}
+	n, err := rewind(r.db, r.s.Chain(), ancestor)
+	if err != nil {
+		log.Error("[scanner] ", r.s.Chain(), " rewind to ", ancestor, " failed: ", err)
+		return
+	}
+	log.Info("[scanner] ", r.s.Chain(), " rewound to ", ancestor, ", reverted ", n, " deposits")
+}
+
+// loadCheckpoint 读取检查点，ok 为 false 表示确实不存在
+// 读取失败必须返回错误，不能当作首次启动，否则会覆盖已有检查点并跳过其后的区块
+func loadCheckpoint(db *gorm.DB, chain, kind string) (model.ScanCheckpoint, bool, error) {
+	var cp model.ScanCheckpoint
+	err := db.Where("chain = ? and kind = ?", chain, kind).Limit(1).Find(&cp).Error
+	return cp, err == nil && cp.Chain != "", err
+}
+
+// initCheckpoint 首次启动时创建检查点，已存在（如其他实例刚创建）时保留原值，返回实际生效的检查点
+func initCheckpoint(db *gorm.DB, chain, kind string, height uint64) (model.ScanCheckpoint, error) {
+	cp := model.ScanCheckpoint{Chain: chain, Kind: kind, Height: height, UpdateTime: time.Now()}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&cp).Error; err != nil {
+		return cp, err
+	}
+	cp, ok, err := loadCheckpoint(db, chain, kind)
+	if err == nil && !ok {
+		err = fmt.Errorf("checkpoint %s %s not created", chain, kind)
+	}
+	return cp, err
+}
+
+func saveCheckpoint(db *gorm.DB, chain, kind string, height uint64, blockHash string) error {
+	cp := model.ScanCheckpoint{Chain: chain, Kind: kind, Height: height, BlockHash: blockHash, UpdateTime: time.Now()}
+	return db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&cp).Error
+}
@@ -0,0 +1,322 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/reguluswee/walletus/common/chain/dep"
+	"github.com/reguluswee/walletus/common/chain/solana"
+	"github.com/reguluswee/walletus/common/config"
+	"github.com/reguluswee/walletus/common/log"
+	"github.com/reguluswee/walletus/common/model"
+	"github.com/reguluswee/walletus/common/system"
+	"golang.org/x/sync/errgroup"
+	"gorm.io/gorm"
+)
+
+const (
+	KindSlot = "slot"
+
+	slotPollInterval = time.Second
+	slotRetryDelay   = 2 * time.Second
+)
+
+// SolanaScanner 将新 slot 放入 system.SlotQueue，由 SlotParallel 个 worker 并发读取区块
+// slot 完成顺序不固定，检查点只推进到连续完成的最高 slot，重启后从其下一个继续
+type SolanaScanner struct {
+	db         *gorm.DB
+	chain      dep.ChainDef
+	client     *solana.SOLClient
+	book       *AddressBook
+	tokens     map[string]bool
+	commitment string
+	lag        uint64
+	window     uint64
+	parallel   int
+	queue      *system.SlotQueue
+	wake       *wakeup
+
+	mu        sync.Mutex
+	next      uint64          // 下一个待入队的 slot
+	watermark uint64          // 该 slot 及之前均已完成
+	done      map[uint64]bool // 已完成但尚未连续的 slot
+	saved     uint64
+}
+
+func NewSolanaScanner(db *gorm.DB, client *solana.SOLClient, book *AddressBook, cc *config.ChainConfig, wake *wakeup) *SolanaScanner {
+	s := &SolanaScanner{
+		db:         db,
+		chain:      dep.GetSupportedSol(),
+		client:     client,
+		book:       book,
+		tokens:     make(map[string]bool, len(cc.Tokens)),
+		commitment: cc.Consistency,
+		lag:        uint64(cc.GetTxDelay()),
+		window:     uint64(cc.GetRangeRound()),
+		parallel:   cc.GetSlotParallel(),
+		queue:      system.NewSlotQueue(),
+		wake:       wake,
+		done:       make(map[uint64]bool),
+	}
+	for _, t := range cc.Tokens {
+		s.tokens[t] = true
+	}
+	return s
+}
+
+// Run 持续入队新 slot 直到 ctx 取消
+func (s *SolanaScanner) Run(ctx context.Context) {
+	log.Info("[scanner] start ", s.chain.Name, " ", KindSlot)
+	go s.queue.Consumer(s.parallel, func(slot uint64, wg *sync.WaitGroup) {
+		defer wg.Done()
+		s.handle(ctx, slot)
+	})
+
+	for {
+		if err := s.produce(ctx); err != nil {
+			log.Error("[scanner] ", s.chain.Name, " produce slots failed: ", err)
+		}
+		s.flush()
+		if !s.wake.wait(ctx, slotPollInterval) {
+			return
+		}
+	}
+}
+
+func (s *SolanaScanner) Chain() string { return s.chain.Name }
+
+func (s *SolanaScanner) Kind() string { return KindSlot }
+
+// Head 按配置的 commitment 读取当前 slot
+func (s *SolanaScanner) Head(ctx context.Context) (uint64, error) {
+	return s.client.Slot(ctx, s.chain.Name, s.commitment)
+}
+
+// FinalizedHead finalized commitment 下的当前 slot
+func (s *SolanaScanner) FinalizedHead(ctx context.Context) (uint64, error) {
+	return s.client.Slot(ctx, s.chain.Name, finalityFinalized)
+}
+
+// produce 入队 (next, head-lag] 内的 slot，未完成的 slot 不超过 window 个
+func (s *SolanaScanner) produce(ctx context.Context) error {
+	head, err := s.Head(ctx)
+	if err != nil {
+		return err
+	}
+	if head <= s.lag {
+		return nil
+	}
+	target := head - s.lag
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.next == 0 {
+		cp, ok, err := loadCheckpoint(s.db, s.chain.Name, KindSlot)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			// 首次启动从当前目标 slot 开始，历史 slot 通过补扫处理
+			if cp, err = initCheckpoint(s.db, s.chain.Name, KindSlot, target-1); err != nil {
+				return err
+			}
+		}
+		s.watermark = cp.Height
+		s.saved = s.watermark
+		s.next = s.watermark + 1
+	}
+	for s.next <= target && s.next-s.watermark <= s.window {
+		s.queue.Enqueue(s.next)
+		s.next++
+	}
+	return nil
+}
+
+// handle 处理单个 slot，跳过的 slot 视为空块；失败的 slot 延迟后重新入队
+func (s *SolanaScanner) handle(ctx context.Context, slot uint64) {
+	if ctx.Err() != nil {
+		return
+	}
+	if err := s.book.Refresh(s.db); err != nil {
+		s.retry(ctx, slot, err)
+		return
+	}
+	deposits, err := s.scanSlot(ctx, slot)
+	if err != nil {
+		s.retry(ctx, slot, err)
+		return
+	}
+	if err := saveDeposits(s.db, deposits); err != nil {
+		s.retry(ctx, slot, err)
+		return
+	}
+	if len(deposits) > 0 {
+		log.Info("[scanner] ", s.chain.Name, " found ", len(deposits), " deposits in slot ", slot)
+	}
+	s.complete(slot)
+}
+
+// scanSlot 读取并匹配单个 slot，被跳过的 slot 视为空块
+func (s *SolanaScanner) scanSlot(ctx context.Context, slot uint64) ([]model.TenantDeposit, error) {
+	b, err := s.client.BlockBySlot(ctx, s.chain.Name, slot, s.commitment)
+	if errors.Is(err, solana.ErrSlotSkipped) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.matchBlock(b), nil
+}
+
+func (s *SolanaScanner) retry(ctx context.Context, slot uint64, err error) {
+	if !solana.IsBlockNotAvailable(err) {
+		log.Error("[scanner] ", s.chain.Name, " slot ", slot, " failed, will retry: ", err)
+	}
+	time.AfterFunc(slotRetryDelay, func() {
+		if ctx.Err() == nil {
+			s.queue.Enqueue(slot)
+		}
+	})
+}
+
+// complete 标记 slot 完成，并推进连续完成的水位
+func (s *SolanaScanner) complete(slot uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.done[slot] = true
+	for s.done[s.watermark+1] {
+		delete(s.done, s.watermark+1)
+		s.watermark++
+	}
+}
+
+// flush 水位变化时写入检查点
+func (s *SolanaScanner) flush() {
+	s.mu.Lock()
+	watermark, saved := s.watermark, s.saved
+	s.mu.Unlock()
+	if watermark == saved {
+		return
+	}
+	if err := saveCheckpoint(s.db, s.chain.Name, KindSlot, watermark, ""); err != nil {
+		log.Error("[scanner] ", s.chain.Name, " save checkpoint failed: ", err)
+		return
+	}
+	s.mu.Lock()
+	s.saved = watermark
+	s.mu.Unlock()
+}
+
+// ScanRange 以 SlotParallel 并发读取 [from, to] 内的 slot，供补扫使用，不经过队列与检查点
+func (s *SolanaScanner) ScanRange(ctx context.Context, from, to uint64) (*scanResult, error) {
+	if err := s.book.Refresh(s.db); err != nil {
+		return nil, err
+	}
+	slots := make([]uint64, 0, to-from+1)
+	for slot := from; slot <= to; slot++ {
+		slots = append(slots, slot)
+	}
+	return s.scanSlots(ctx, slots)
+}
+
+// ScanAddress 通过地址及其各代币 Token 账户的交易签名找到相关 slot，只读取这些 slot
+func (s *SolanaScanner) ScanAddress(ctx context.Context, address string, from, to uint64) (*scanResult, error) {
+	if err := s.book.Refresh(s.db); err != nil {
+		return nil, err
+	}
+	accounts := []string{address}
+	for token := range s.tokens {
+		ta, err := s.client.TokenAccounts(ctx, s.chain.Name, address, token)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, ta...)
+	}
+
+	seen := make(map[uint64]bool)
+	var slots []uint64
+	for _, acc := range accounts {
+		found, err := s.client.AddressSlots(ctx, s.chain.Name, acc, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, slot := range found {
+			if !seen[slot] {
+				seen[slot] = true
+				slots = append(slots, slot)
+			}
+		}
+	}
+	slices.Sort(slots)
+	return s.scanSlots(ctx, slots)
+}
+
+func (s *SolanaScanner) scanSlots(ctx context.Context, slots []uint64) (*scanResult, error) {
+	found := make([][]model.TenantDeposit, len(slots))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(s.parallel)
+	for i, slot := range slots {
+		i, slot := i, slot
+		g.Go(func() error {
+			deposits, err := s.scanSlot(gctx, slot)
+			found[i] = deposits
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	res := &scanResult{}
+	for _, deposits := range found {
+		res.Deposits = append(res.Deposits, deposits...)
+	}
+	return res, nil
+}
+
+func (s *SolanaScanner) matchBlock(b *solana.Block) []model.TenantDeposit {
+	var out []model.TenantDeposit
+	for _, t := range b.Transfers {
+		if t.Token != "" && !s.tokens[t.Token] {
+			continue
+		}
+		ref, ok := s.book.Lookup(t.To)
+		if !ok {
+			continue
+		}
+		// 同一交易可包含多笔 SOL 转账，原生币同样使用指令序号区分
+		out = append(out, newDeposit(s.chain.Name, ref, t.Token, t.From, t.Amount, t.Signature, t.Index, b.Slot, b.Hash))
+	}
+	return out
+}
+
+// RunSolana 启动 Solana slot 扫描与入账确认，直到 ctx 取消
+// 配置了 WsRpc 时订阅 slotSubscribe 及代币 mint 的 logsSubscribe 推送唤醒扫描
+// slotSubscribe 推送的是 processed 级别的 slot，与配置的 commitment 不一致，只用于唤醒，链头仍通过 RPC 读取
+func RunSolana(ctx context.Context, db *gorm.DB, cc *config.ChainConfig) {
+	chain := dep.GetSupportedSol()
+
+	var slotWake, confirmWake *wakeup
+	var wg sync.WaitGroup
+	if cc.WsRpc != "" {
+		slotWake, confirmWake = newWakeup(), newWakeup()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			keepSubscribed(ctx, chain.Name+" slots", false, func(ctx context.Context, out chan<- uint64) error {
+				return solana.Subscribe(ctx, cc.WsRpc, cc.Tokens, cc.Consistency, out)
+			}, slotWake, confirmWake)
+		}()
+	}
+
+	s := NewSolanaScanner(db, solana.NewSOLClient(), NewAddressBook(chain.Name, false), cc, slotWake)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		NewConfirmer(db, chain.Name, s, cc, confirmWake).Run(ctx)
+	}()
+	s.Run(ctx)
+	wg.Wait()
+}
@@ -0,0 +1,21 @@
+package scanner
+
+import "testing"
+
+func TestSolanaWatermark(t *testing.T) {
+	s := &SolanaScanner{watermark: 100, done: make(map[uint64]bool)}
+
+	s.complete(102)
+	s.complete(103)
+	if s.watermark != 100 {
+		t.Fatalf("watermark moved past a gap: %d", s.watermark)
+	}
+	s.complete(101)
+	if s.watermark != 103 || len(s.done) != 0 {
+		t.Fatalf("watermark = %d, pending = %v", s.watermark, s.done)
+	}
+	s.complete(105)
+	if s.watermark != 103 || !s.done[105] {
+		t.Fatalf("watermark = %d, pending = %v", s.watermark, s.done)
+	}
+}
@@ -0,0 +1,143 @@
+package scanner
+
+import (
+	"context"
+	"sync"
+
+	"github.com/reguluswee/walletus/common/chain/dep"
+	"github.com/reguluswee/walletus/common/chain/tron"
+	"github.com/reguluswee/walletus/common/config"
+	"github.com/reguluswee/walletus/common/log"
+	"github.com/reguluswee/walletus/common/model"
+	"golang.org/x/sync/errgroup"
+	"gorm.io/gorm"
+)
+
+// tronChunk 每个并发任务读取的区块数，与 getblockbylimitnext 的单次上限一致
+const tronChunk = 100
+
+// TronScanner 读取区块中的 TransferContract 与 TRC-20 调用，识别转入租户地址的 TRX 与白名单代币
+type TronScanner struct {
+	db       *gorm.DB
+	chain    dep.ChainDef
+	client   *tron.TRXClient
+	book     *AddressBook
+	tokens   map[string]bool
+	solid    bool
+	parallel int
+}
+
+func NewTronScanner(db *gorm.DB, chain dep.ChainDef, client *tron.TRXClient, book *AddressBook, cc *config.ChainConfig) *TronScanner {
+	s := &TronScanner{
+		db:       db,
+		chain:    chain,
+		client:   client,
+		book:     book,
+		tokens:   make(map[string]bool, len(cc.Tokens)),
+		solid:    cc.Consistency == "latest_solid",
+		parallel: cc.GetSlotParallel(),
+	}
+	for _, t := range cc.Tokens {
+		if !tron.ValidAddress(t) {
+			log.Error("[scanner] ", chain.Name, " ignore invalid token: ", t)
+			continue
+		}
+		s.tokens[t] = true
+	}
+	return s
+}
+
+func (s *TronScanner) Chain() string { return s.chain.Name }
+
+func (s *TronScanner) Kind() string { return KindBlock }
+
+// Head latest_solid 模式下读取固化节点高度，只扫描不可回滚的区块
+func (s *TronScanner) Head(ctx context.Context) (uint64, error) {
+	return s.client.BlockNumber(ctx, s.chain.Name, s.solid)
+}
+
+// FinalizedHead 固化节点高度，固化块不可回滚
+func (s *TronScanner) FinalizedHead(ctx context.Context) (uint64, error) {
+	return s.client.BlockNumber(ctx, s.chain.Name, true)
+}
+
+// ScanRange 按 100 个区块分段并发读取
+func (s *TronScanner) ScanRange(ctx context.Context, from, to uint64) (*scanResult, error) {
+	if err := s.book.Refresh(s.db); err != nil {
+		return nil, err
+	}
+
+	var chunks [][2]uint64
+	for start := from; start <= to; start += tronChunk {
+		chunks = append(chunks, [2]uint64{start, min(start+tronChunk-1, to)})
+	}
+	results := make([][]*tron.Block, len(chunks))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(s.parallel)
+	for i, c := range chunks {
+		i, c := i, c
+		g.Go(func() error {
+			blocks, err := s.client.BlocksByRange(gctx, s.chain.Name, c[0], c[1])
+			if err != nil {
+				return err
+			}
+			results[i] = blocks
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	res := &scanResult{}
+	for _, blocks := range results {
+		for _, b := range blocks {
+			res.Deposits = append(res.Deposits, s.matchBlock(b)...)
+			res.Blocks = append(res.Blocks, model.ScanBlock{Height: b.Number, Hash: b.Hash, ParentHash: b.ParentHash})
+		}
+	}
+	return res, nil
+}
+
+func (s *TronScanner) BlockHash(ctx context.Context, height uint64) (string, error) {
+	b, err := s.client.BlockByNumber(ctx, s.chain.Name, height)
+	if err != nil {
+		return "", err
+	}
+	return b.Hash, nil
+}
+
+func (s *TronScanner) matchBlock(b *tron.Block) []model.TenantDeposit {
+	var out []model.TenantDeposit
+	for _, t := range b.Transfers {
+		ref, ok := s.book.Lookup(t.To)
+		if !ok {
+			continue
+		}
+		logIndex := model.NativeLogIndex
+		if t.Token != "" {
+			if !s.tokens[t.Token] {
+				continue
+			}
+			// TRON 交易只含一个合约调用，代币转账固定为 0
+			logIndex = 0
+		}
+		out = append(out, newDeposit(s.chain.Name, ref, t.Token, t.From, t.Amount, t.TxID, logIndex, b.Number, b.Hash))
+	}
+	return out
+}
+
+// RunTron 启动 TRON 区块扫描与入账确认，直到 ctx 取消
+func RunTron(ctx context.Context, db *gorm.DB, cc *config.ChainConfig) {
+	chain := dep.GetSupportedTron()
+	s := NewTronScanner(db, chain, tron.NewTRXClient(), NewAddressBook(chain.Name, false), cc)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		NewConfirmer(db, chain.Name, s, cc, nil).Run(ctx)
+	}()
+	newRunner(db, s, cc, nil).Run(ctx)
+	wg.Wait()
+}
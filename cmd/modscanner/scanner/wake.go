@@ -0,0 +1,130 @@
+package scanner
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/reguluswee/walletus/common/log"
+)
+
+const (
+	// 两轮扫描之间的最小间隔，出块很快的链上推送不会导致频繁请求
+	minWakeInterval = time.Second
+	// 订阅正常时的兜底轮询间隔
+	idlePollInterval = 30 * time.Second
+	// 推送的链头超过该时间未更新时不再使用，退回 RPC 查询
+	headMaxAge = 30 * time.Second
+
+	wsRetryMin = 2 * time.Second
+	wsRetryMax = time.Minute
+)
+
+// wakeup WebSocket 推送的唤醒信号及最新链头
+// 订阅正常时收到推送立即扫描、兜底轮询放慢；连接断开期间退回 HTTP 轮询，由检查点补齐断开期间的区块
+// nil 表示未配置 WebSocket，wait 等同于按 poll 轮询
+type wakeup struct {
+	ch        chan struct{}
+	connected atomic.Bool
+	head      atomic.Uint64
+	headTime  atomic.Int64
+}
+
+func newWakeup() *wakeup {
+	return &wakeup{ch: make(chan struct{}, 1)}
+}
+
+// notify 唤醒等待方，head 大于 0 时同时记录最新链头
+func (w *wakeup) notify(head uint64) {
+	w.connected.Store(true)
+	if head > 0 && head >= w.head.Load() {
+		w.head.Store(head)
+		w.headTime.Store(time.Now().UnixNano())
+	}
+	select {
+	case w.ch <- struct{}{}:
+	default:
+	}
+}
+
+func (w *wakeup) disconnect() {
+	w.connected.Store(false)
+}
+
+// Head 推送的最新链头，订阅断开或长时间未更新时返回 false
+func (w *wakeup) Head() (uint64, bool) {
+	if w == nil || !w.connected.Load() || w.head.Load() == 0 {
+		return 0, false
+	}
+	if time.Since(time.Unix(0, w.headTime.Load())) > headMaxAge {
+		return 0, false
+	}
+	return w.head.Load(), true
+}
+
+// wait 等待推送或轮询超时，ctx 取消时返回 false
+func (w *wakeup) wait(ctx context.Context, poll time.Duration) bool {
+	if w == nil || !w.connected.Load() {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(poll):
+			return true
+		}
+	}
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(minWakeInterval):
+	}
+	select {
+	case <-ctx.Done():
+		return false
+	case <-w.ch:
+	case <-time.After(idlePollInterval - minWakeInterval):
+	}
+	return true
+}
+
+// keepSubscribed 保持一个 WebSocket 订阅，断开后按退避重连，直到 ctx 取消
+// subscribe 阻塞运行并把通知中的高度写入 out；withHead 为 true 时该高度作为链头记录到 targets
+func keepSubscribed(ctx context.Context, name string, withHead bool, subscribe func(ctx context.Context, out chan<- uint64) error, targets ...*wakeup) {
+	delay := wsRetryMin
+	for ctx.Err() == nil {
+		out := make(chan uint64, 16)
+		done := make(chan error, 1)
+		go func() {
+			done <- subscribe(ctx, out)
+		}()
+
+		var err error
+	recv:
+		for {
+			select {
+			case h := <-out:
+				if !withHead {
+					h = 0
+				}
+				for _, t := range targets {
+					t.notify(h)
+				}
+				delay = wsRetryMin
+			case err = <-done:
+				break recv
+			}
+		}
+		for _, t := range targets {
+			t.disconnect()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		log.Error("[scanner] ", name, " subscription dropped, retry in ", delay, ": ", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, wsRetryMax)
+	}
+}
@@ -0,0 +1,47 @@
+package scanner
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWakeupHead(t *testing.T) {
+	var nilWake *wakeup
+	if _, ok := nilWake.Head(); ok {
+		t.Fatal("nil wakeup should not report a head")
+	}
+
+	w := newWakeup()
+	w.notify(0)
+	if _, ok := w.Head(); ok {
+		t.Fatal("wake-only notify should not record a head")
+	}
+	w.notify(100)
+	w.notify(99)
+	if h, ok := w.Head(); !ok || h != 100 {
+		t.Fatalf("head = %d %v, want 100 true", h, ok)
+	}
+
+	w.headTime.Store(time.Now().Add(-headMaxAge - time.Second).UnixNano())
+	if _, ok := w.Head(); ok {
+		t.Fatal("stale head should be ignored")
+	}
+	w.notify(101)
+	w.disconnect()
+	if _, ok := w.Head(); ok {
+		t.Fatal("disconnected head should be ignored")
+	}
+}
+
+func TestWakeupWaitCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if newWakeup().wait(ctx, time.Minute) {
+		t.Fatal("wait should return false once ctx is cancelled")
+	}
+	var nilWake *wakeup
+	if !nilWake.wait(context.Background(), time.Millisecond) {
+		t.Fatal("nil wakeup should fall back to polling")
+	}
+}
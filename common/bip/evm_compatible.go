@@ -108,7 +108,7 @@ func DeriveEvmAddressFromXpub(xpub string, tenantIndex, addressIndex uint32, cha
 	ecdsaPub := pub.ToECDSA()
 
 	switch chainDef.Name {
-	case "ETH", "BSC", "OP", "ARB", "POLYGON":
+	case "ETH", "BSC", "OP", "ARB", "POLYGON", "BSC_TESTNET":
 		// EVM 兼容链地址：keccak(pub) 后 20 字节
 		addr = gethcrypto.PubkeyToAddress(*ecdsaPub).Hex()
 	case "TRON":
@@ -145,7 +145,7 @@ func AddressAndPrivFromPath(enc EncMaster, path, chainCode string) (addr string,
 
 	// 3) 计算不同链的地址
 	switch chainCode {
-	case "ETH", "BSC", "OP", "ARB", "POLYGON":
+	case "ETH", "BSC", "OP", "ARB", "POLYGON", "BSC_TESTNET":
 		addr = gethcrypto.PubkeyToAddress(*pub).Hex() // 0x...
 	case "TRON":
 		evm20 := gethcrypto.PubkeyToAddress(*pub).Bytes() // 20 bytes
@@ -1,9 +1,11 @@
 package bip
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/reguluswee/walletus/common/chain/dep"
 	bip39 "github.com/tyler-smith/go-bip39"
 )
 
@@ -25,6 +27,19 @@ type EncMaster struct {
 	KDF           KDFParams `json:"kdf_params"`
 }
 
+// EncMasterFromKeyRef 由签名参数中的密钥引用还原租户加密主密钥
+func EncMasterFromKeyRef(k dep.KeyRef) (EncMaster, error) {
+	var kdf KDFParams
+	if err := json.Unmarshal([]byte(k.KdfParams), &kdf); err != nil {
+		return EncMaster{}, fmt.Errorf("kdf params error: %v", err)
+	}
+	return EncMaster{
+		EncMasterXprv: k.EncMasterXprv,
+		EncMasterSeed: k.EncMasterSeed,
+		KDF:           kdf,
+	}, nil
+}
+
 func GenerateMasterSeed() ([]byte, error) {
 	entropy, err := bip39.NewEntropy(128)
 	if err != nil {
@@ -30,6 +30,17 @@ func DeriveSOL(enc EncMaster, tenantIdx, addrIdx uint32) (DerivedSOL, error) {
 	return deriveSOLFromSeed(seed, path)
 }
 
+// DeriveSOLFromPath 按已保存的派生路径（tenant_address.derived_path）还原 ed25519 密钥
+func DeriveSOLFromPath(enc EncMaster, path string) (DerivedSOL, error) {
+	seed, err := decryptMasterSeed(enc, []byte(tenantSecretPassword))
+	if err != nil {
+		return DerivedSOL{}, err
+	}
+	defer zero(seed)
+
+	return deriveSOLFromSeed(seed, path)
+}
+
 func deriveSOLFromSeed(seed []byte, path string) (DerivedSOL, error) {
 	// SLIP-0010(ed25519)，全硬化路径
 	node, err := slip10.DeriveForPath(path, seed)
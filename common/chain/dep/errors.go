@@ -0,0 +1,77 @@
+package dep
+
+import (
+	"errors"
+	"strings"
+)
+
+// 广播错误类型
+var (
+	ErrAlreadyKnown      = errors.New("transaction already known")
+	ErrNonceTooLow       = errors.New("nonce too low")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrUnderpriced       = errors.New("transaction underpriced")
+	ErrTxInvalid         = errors.New("transaction expired or invalid") // blockhash / 引用区块过期、nonce 过高、签名无效等，原样重播不会成功
+)
+
+// BroadcastError 广播失败时携带节点原始错误信息
+type BroadcastError struct {
+	Kind error // 上述错误类型之一，无法识别时为 ErrRPCFailed
+	Msg  string
+}
+
+func (e *BroadcastError) Error() string {
+	return e.Kind.Error() + ": " + e.Msg
+}
+
+func (e *BroadcastError) Unwrap() error {
+	return e.Kind
+}
+
+// 各链节点返回的错误关键字（小写匹配）
+var broadcastErrorPatterns = []struct {
+	kind     error
+	keywords []string
+}{
+	{ErrAlreadyKnown, []string{
+		"already known", "known transaction", "already imported", "alreadyknown",
+		"dup_transaction_error",                      // TRON
+		"already been processed", "alreadyprocessed", // Solana
+	}},
+	{ErrNonceTooLow, []string{
+		"nonce too low", "nonce is too low", "oldnonce",
+	}},
+	{ErrUnderpriced, []string{
+		"underpriced", "less than block base fee", "gas price too low", "fee too low",
+	}},
+	{ErrInsufficientFunds, []string{
+		"insufficient funds", "insufficient balance", "insufficient lamports",
+		"balance is not sufficient", "bandwith_error", // TRON
+		"no record of a prior credit", // Solana
+	}},
+	{ErrTxInvalid, []string{
+		"nonce too high", "intrinsic gas too low", "invalid sender", "exceeds block gas limit",
+		"transaction_expiration_error", "tapos_error", "sigerror", "too_big_transaction_error", // TRON
+		"blockhash not found", "blockhashnotfound", "signature verification failure", // Solana
+	}},
+}
+
+// IsRejected 节点明确拒绝了交易（nonce 过低、余额不足、费用过低、交易过期或无效），交易不会进入交易池
+// 超时、限流及无法识别的错误（ErrRPCFailed）不算拒绝，交易可能已被节点接收
+func IsRejected(err error) bool {
+	return errors.Is(err, ErrNonceTooLow) || errors.Is(err, ErrInsufficientFunds) || errors.Is(err, ErrUnderpriced) ||
+		errors.Is(err, ErrTxInvalid)
+}
+
+// ClassifyBroadcastError 将节点返回的错误信息归类为 BroadcastError
+func ClassifyBroadcastError(msg string) *BroadcastError {
+	lower := strings.ToLower(msg)
+	for _, p := range broadcastErrorPatterns {
+		for _, k := range p.keywords {
+			if strings.Contains(lower, k) {
+				return &BroadcastError{Kind: p.kind, Msg: msg}
+			}
+		}
+	}
+	return &BroadcastError{Kind: ErrRPCFailed, Msg: msg}
+}
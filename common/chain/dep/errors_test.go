@@ -0,0 +1,57 @@
+package dep
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestClassifyBroadcastError(t *testing.T) {
+	cases := []struct {
+		msg  string
+		want error
+	}{
+		{"already known", ErrAlreadyKnown},
+		{"DUP_TRANSACTION_ERROR: dup transaction", ErrAlreadyKnown},
+		{"rpc error -32002: Transaction simulation failed: This transaction has already been processed", ErrAlreadyKnown},
+		{"nonce too low: next nonce 12, tx nonce 11", ErrNonceTooLow},
+		{"replacement transaction underpriced", ErrUnderpriced},
+		{"max fee per gas less than block base fee", ErrUnderpriced},
+		{"insufficient funds for gas * price + value", ErrInsufficientFunds},
+		{"CONTRACT_VALIDATE_ERROR: Validate TransferContract error, balance is not sufficient.", ErrInsufficientFunds},
+		{"Attempt to debit an account but found no record of a prior credit.", ErrInsufficientFunds},
+		{"nonce too high", ErrTxInvalid},
+		{"TRANSACTION_EXPIRATION_ERROR: Transaction expired", ErrTxInvalid},
+		{"TAPOS_ERROR: Tapos check error", ErrTxInvalid},
+		{"rpc error -32002: Transaction simulation failed: Blockhash not found", ErrTxInvalid},
+		{"SERVER_BUSY", ErrRPCFailed},
+	}
+	for _, c := range cases {
+		err := ClassifyBroadcastError(c.msg)
+		if !errors.Is(err, c.want) {
+			t.Errorf("%q classified as %v, want %v", c.msg, err.Kind, c.want)
+		}
+	}
+}
+
+func TestIsRejected(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{ClassifyBroadcastError("nonce too low"), true},
+		{ClassifyBroadcastError("insufficient funds for gas * price + value"), true},
+		{ClassifyBroadcastError("replacement transaction underpriced"), true},
+		{ClassifyBroadcastError("TRANSACTION_EXPIRATION_ERROR"), true},
+		{ClassifyBroadcastError("Blockhash not found"), true},
+		{ClassifyBroadcastError("already known"), false},
+		{ClassifyBroadcastError("context deadline exceeded"), false},
+		{ClassifyBroadcastError("SERVER_BUSY"), false},
+		{errors.New("i/o timeout"), false},
+		{nil, false},
+	}
+	for _, c := range cases {
+		if got := IsRejected(c.err); got != c.want {
+			t.Errorf("IsRejected(%v) = %v, want %v", c.err, got, c.want)
+		}
+	}
+}
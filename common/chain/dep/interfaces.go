@@ -1,6 +1,9 @@
 package dep
 
-import "context"
+import (
+	"context"
+	"math/big"
+)
 
 type Reader interface {
 	Anchor(ctx context.Context, network string, c Consistency) (AnchorRef, error)
@@ -13,20 +16,38 @@ type Reader interface {
 	NativeBalanceBatch(ctx context.Context, network string, addresses []string, anchor AnchorRef) (map[string]*NativeBalance, error)
 	TokenBalancesBatch(ctx context.Context, network string, addressToTokens map[string][]string, anchor AnchorRef) (map[string][]TokenBalance, error)
 
-	GetTransaction(ctx context.Context, network string, txHash string) (any, error)
+	// GetTransaction 读取交易回执，交易不存在时返回 Status 为 TxStatusNotFound 的回执；确认数相对 anchor 计算
+	GetTransaction(ctx context.Context, network string, txHash string, anchor AnchorRef) (*TxReceipt, error)
 }
 
+// Signer 构建并签名转账交易，amount 为最小单位的十进制字符串，opts 为 SignOptions
 type Signer interface {
 	SignTransferNative(ctx context.Context, network string, from, to string, amount string, opts any) (rawTx []byte, txHash string, err error)
 	SignTransferToken(ctx context.Context, network, token, from, to string, amount string, opts any) (rawTx []byte, txHash string, err error)
 }
 
-type Boradcaster interface {
-	Boradcast(ctx context.Context, network string, rawTx []byte)
+// Broadcaster 广播已签名交易，返回交易哈希
+// 失败时返回 *BroadcastError，可用 errors.Is 判断 ErrAlreadyKnown / ErrNonceTooLow 等类型；
+// 同一笔交易重复广播时返回交易哈希与 ErrAlreadyKnown
+type Broadcaster interface {
+	Broadcast(ctx context.Context, network string, rawTx []byte) (txHash string, err error)
 }
 
+// Sweeper 构建并签名归集交易，opts 为 SweepOptions
+// 原生币归集余额扣除手续费后的全部金额，Token 归集全部余额；amount 为实际归集的最小单位金额
 type Sweeper interface {
-	BuildAndSignSweep(ctx context.Context, network string, from string, dest string, opts any) (rawTx []byte, txHash string, err error)
+	BuildAndSignSweep(ctx context.Context, network string, from string, dest string, opts any) (rawTx []byte, txHash string, amount string, err error)
+}
+
+// GasQuoter 计算 from 发起一笔代币转账前至少需要持有的原生币数量（最小单位）
+// 结果已包含手续费，以及链上要求的最低余额（如 Solana 免租金额）
+type GasQuoter interface {
+	QuoteTokenTransferGas(ctx context.Context, network, token, from, to string, amount string) (*big.Int, error)
+}
+
+// FeeEstimator 估算一笔转账的手续费，Token 为空时估算原生币转账
+type FeeEstimator interface {
+	EstimateFee(ctx context.Context, network string, q FeeQuery) (*FeeEstimate, error)
 }
 
 type Client interface {
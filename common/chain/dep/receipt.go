@@ -0,0 +1,48 @@
+package dep
+
+import "math/big"
+
+// 交易回执状态
+const (
+	TxStatusPending  = "pending"   // 已被节点接收，尚未打包
+	TxStatusSuccess  = "success"   // 已打包且执行成功
+	TxStatusFailed   = "failed"    // 已打包但执行失败（revert、能量不足等），手续费已扣除
+	TxStatusNotFound = "not_found" // 节点上查不到该交易
+)
+
+// TxTransfer 交易中的一笔转账，Token 为空表示原生币，Amount 为最小单位
+type TxTransfer struct {
+	Token  string   `json:"token,omitempty"`
+	From   string   `json:"from"`
+	To     string   `json:"to"`
+	Amount *big.Int `json:"amount"`
+}
+
+// TxReceipt 链无关的交易回执，业务代码只依赖该结构
+// Confirmations 相对 Anchor 计算，未打包或位于 Anchor 之后时为 0；Fee 为原生币最小单位
+// Transfers 只在执行成功时返回，EVM 与 TRON 为原生币转账及 Transfer 事件，Solana 为解析出的 SOL / SPL 转账指令
+type TxReceipt struct {
+	TxHash        string       `json:"tx_hash"`
+	Status        string       `json:"status"`
+	BlockHeight   uint64       `json:"block_height"`
+	BlockTime     int64        `json:"block_time,omitempty"` // 秒级时间戳，节点未返回时为 0
+	Confirmations uint64       `json:"confirmations"`
+	Anchor        AnchorRef    `json:"anchor"`
+	Fee           *big.Int     `json:"fee"`
+	Transfers     []TxTransfer `json:"transfers"`
+	Error         string       `json:"error,omitempty"`
+}
+
+// SetAnchor 记录锚点并计算确认数，交易所在区块本身计为 1 个确认
+func (r *TxReceipt) SetAnchor(a AnchorRef) {
+	r.Anchor = a
+	r.Confirmations = 0
+	if r.BlockHeight > 0 && a.Height >= r.BlockHeight {
+		r.Confirmations = a.Height - r.BlockHeight + 1
+	}
+}
+
+// Final 交易已打包且结果确定（成功或失败）
+func (r *TxReceipt) Final() bool {
+	return r.Status == TxStatusSuccess || r.Status == TxStatusFailed
+}
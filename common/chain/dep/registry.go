@@ -25,3 +25,53 @@ func GetClient(chain ChainDef) (Client, bool) {
 	e, ok := registry[ChainCode(chain.Name)]
 	return e.reader, ok
 }
+
+// GetSigner 返回支持签名的链客户端
+func GetSigner(chain ChainDef) (Signer, bool) {
+	c, ok := GetClient(chain)
+	if !ok {
+		return nil, false
+	}
+	s, ok := c.(Signer)
+	return s, ok
+}
+
+// GetBroadcaster 返回支持广播的链客户端
+func GetBroadcaster(chain ChainDef) (Broadcaster, bool) {
+	c, ok := GetClient(chain)
+	if !ok {
+		return nil, false
+	}
+	b, ok := c.(Broadcaster)
+	return b, ok
+}
+
+// GetSweeper 返回支持归集的链客户端
+func GetSweeper(chain ChainDef) (Sweeper, bool) {
+	c, ok := GetClient(chain)
+	if !ok {
+		return nil, false
+	}
+	sw, ok := c.(Sweeper)
+	return sw, ok
+}
+
+// GetGasQuoter 返回支持代币转账 gas 报价的链客户端
+func GetGasQuoter(chain ChainDef) (GasQuoter, bool) {
+	c, ok := GetClient(chain)
+	if !ok {
+		return nil, false
+	}
+	q, ok := c.(GasQuoter)
+	return q, ok
+}
+
+// GetFeeEstimator 返回支持手续费估算的链客户端
+func GetFeeEstimator(chain ChainDef) (FeeEstimator, bool) {
+	c, ok := GetClient(chain)
+	if !ok {
+		return nil, false
+	}
+	e, ok := c.(FeeEstimator)
+	return e, ok
+}
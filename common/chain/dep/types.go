@@ -98,8 +98,110 @@ type BatchBalanceResult struct {
 	Results []BalanceResult
 }
 
+// KeyRef 描述签名私钥的来源：租户加密主密钥 + BIP44 派生路径
+// 私钥只在签名时临时派生，不落地
+type KeyRef struct {
+	EncMasterXprv string
+	EncMasterSeed string
+	KdfParams     string // JSON 格式的 KDF 参数，与 tenant_information.kdf_params 一致
+	Path          string // 如 m/44'/60'/1'/0/2
+}
+
+// SignOptions 作为 Signer 的 opts 传入，除 Key 外均为可选项
+type SignOptions struct {
+	Key KeyRef
+
+	// EVM：为空时分别从链上获取 nonce / 估算 gas / 读取费用
+	Nonce                *uint64
+	GasLimit             uint64
+	GasPrice             *big.Int
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+	Legacy               bool // 强制使用 legacy 交易
+
+	// TRON：合约调用愿意燃烧的 TRX 上限（sun），为 0 时使用默认值
+	FeeLimit int64
+}
+
+// ToSignOptions 将 Signer 的 opts 参数转换为 SignOptions
+func ToSignOptions(opts any) (*SignOptions, error) {
+	switch v := opts.(type) {
+	case SignOptions:
+		return &v, nil
+	case *SignOptions:
+		if v != nil {
+			return v, nil
+		}
+	}
+	return nil, ErrInvalidSignOptions
+}
+
+// SweepOptions 作为 Sweeper 的 opts 传入
+type SweepOptions struct {
+	SignOptions
+	Token string // 为空时归集原生币
+}
+
+// ToSweepOptions 将 Sweeper 的 opts 参数转换为 SweepOptions
+func ToSweepOptions(opts any) (*SweepOptions, error) {
+	switch v := opts.(type) {
+	case SweepOptions:
+		return &v, nil
+	case *SweepOptions:
+		if v != nil {
+			return v, nil
+		}
+	}
+	return nil, ErrInvalidSignOptions
+}
+
+// FeeQuery 手续费估算参数，From/To/Amount 可选，提供时估算更准确
+type FeeQuery struct {
+	Token  string
+	From   string
+	To     string
+	Amount string
+}
+
+// FeeTier 一档手续费，金额均为原生币最小单位
+// EVM：PriorityFee/MaxFee 为每 gas 的价格；Solana：PriorityFee 为每计算单元的 micro-lamports
+type FeeTier struct {
+	Level       string   `json:"level"`
+	PriorityFee *big.Int `json:"priority_fee"`
+	MaxFee      *big.Int `json:"max_fee,omitempty"`
+	Fee         *big.Int `json:"fee"`
+}
+
+// FeeEstimate 手续费估算结果，不同链只填充各自相关的字段
+type FeeEstimate struct {
+	Symbol   string    `json:"symbol"`
+	Decimals int       `json:"decimals"`
+	BaseFee  *big.Int  `json:"base_fee"`
+	GasLimit uint64    `json:"gas_limit,omitempty"`
+	Tiers    []FeeTier `json:"tiers"`
+
+	// TRON：能量与带宽用量及单价（sun）
+	Energy         int64 `json:"energy,omitempty"`
+	EnergyPrice    int64 `json:"energy_price,omitempty"`
+	Bandwidth      int64 `json:"bandwidth,omitempty"`
+	BandwidthPrice int64 `json:"bandwidth_price,omitempty"`
+}
+
+// ParseAmount 解析最小单位（wei/sun/lamports）的十进制金额
+func ParseAmount(s string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok || n.Sign() < 0 {
+		return nil, ErrInvalidAmount
+	}
+	return n, nil
+}
+
 var (
-	ErrUnsupportedChain = fmt.Errorf("unsupported chain code")
-	ErrInvalidAddress   = fmt.Errorf("invalid address")
-	ErrRPCFailed        = fmt.Errorf("rpc failed")
+	ErrUnsupportedChain   = fmt.Errorf("unsupported chain code")
+	ErrInvalidAddress     = fmt.Errorf("invalid address")
+	ErrRPCFailed          = fmt.Errorf("rpc failed")
+	ErrInvalidAmount      = fmt.Errorf("invalid amount")
+	ErrInvalidSignOptions = fmt.Errorf("invalid sign options")
+	ErrKeyMismatch        = fmt.Errorf("derived key does not match from address")
+	ErrNothingToSweep     = fmt.Errorf("balance not enough to sweep")
 )
@@ -10,27 +10,20 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	gethrpc "github.com/ethereum/go-ethereum/rpc"
 	"github.com/reguluswee/walletus/common/chain/dep"
+	"github.com/reguluswee/walletus/common/chain/provider"
 	"github.com/reguluswee/walletus/common/config"
 	"golang.org/x/crypto/sha3"
 )
 
-func (c *EVMClient) pick(network string) (*gethrpc.Client, string, error) {
-	pool, err := c.ensurePool(network)
-	if err != nil {
-		return nil, "", err
-	}
-	// TODO 取第一个（可扩展为加权/熔断/健康检查）
-	if len(pool.clients) == 0 {
-		return nil, "", fmt.Errorf("no RPC available for %s", network)
-	}
-	return pool.clients[0], pool.names[0], nil
+func (c *EVMClient) pick(network string) (*rpcClient, error) {
+	return c.ensurePool(network)
 }
 
 // ensurePool 确保指定网络的 RPC 连接池已初始化
 // 该方法会从系统配置中读取 RPC 端点配置（通过 config.GetRpcConfig）
 // 配置文件的路径由 config 包管理，通常从 dev.yml 或环境变量指定
 // RPC 配置格式：chain[].name 必须与 network 参数匹配，chain[].queryRpc 包含 RPC 端点列表
-func (c *EVMClient) ensurePool(network string) (*rpcPool, error) {
+func (c *EVMClient) ensurePool(network string) (*rpcClient, error) {
 	c.mu.RLock()
 	p, ok := c.pools[network]
 	c.mu.RUnlock()
@@ -52,25 +45,19 @@ func (c *EVMClient) ensurePool(network string) (*rpcPool, error) {
 		return nil, fmt.Errorf("missing RPC config for %s", network)
 	}
 
-	// 创建 RPC 连接池
-	var pool rpcPool
-	for _, url := range cc.GetRpc() {
-		if rc, err := gethrpc.Dial(url); err == nil {
-			pool.clients = append(pool.clients, rc)
-			pool.names = append(pool.names, shortAlias(network, url))
-		}
-		// 注意：如果某个 RPC 连接失败，会静默跳过，只使用成功连接的 RPC
-	}
-	if len(pool.clients) == 0 {
-		return nil, fmt.Errorf("dial RPC failed for %s", network)
+	// 创建 RPC 连接池，拨号失败的端点会被跳过
+	pool, err := provider.New(network, cc.GetRpcMapper(), gethrpc.Dial, isEndpointFailure)
+	if err != nil {
+		return nil, err
 	}
-	c.pools[network] = &pool
+	p = &rpcClient{pool: pool}
+	c.pools[network] = p
 
 	// 初始化 Multicall 地址（如果尚未初始化）
 	if _, ok := c.mcAddr[network]; !ok {
 		c.mcAddr[network] = resolveMulticall(network)
 	}
-	return &pool, nil
+	return p, nil
 }
 
 func (c *EVMClient) getMulticallAddr(network string) string {
@@ -81,20 +68,6 @@ func (c *EVMClient) getMulticallAddr(network string) string {
 
 // ---------------- 工具函数 ----------------
 
-func shortAlias(chain, url string) string {
-	u := url
-	if i := strings.Index(u, "://"); i >= 0 {
-		u = u[i+3:]
-	}
-	if j := strings.Index(u, "/"); j >= 0 {
-		u = u[:j]
-	}
-	if len(u) > 28 {
-		u = u[:28]
-	}
-	return strings.ToLower(chain) + ":" + u
-}
-
 func nativeSymbol(network string) string {
 	switch strings.ToLower(network) {
 	case "bsc", "bnb", "bnbchain":
@@ -237,7 +210,7 @@ func resolveMulticall(network string) string {
 }
 
 func (c *EVMClient) multicallBalanceOf(ctx context.Context, network string, pairs [][2]common.Address, a dep.AnchorRef) (map[[2]common.Address]*big.Int, error) {
-	rc, _, err := c.pick(network)
+	rc, err := c.pick(network)
 	if err != nil {
 		return nil, err
 	}
@@ -296,7 +269,7 @@ func (c *EVMClient) multicallBalanceOf(ctx context.Context, network string, pair
 }
 
 func (c *EVMClient) batchedBalanceOf(ctx context.Context, network string, pairs [][2]common.Address, a dep.AnchorRef) (map[[2]common.Address]*big.Int, error) {
-	rc, _, err := c.pick(network)
+	rc, err := c.pick(network)
 	if err != nil {
 		return nil, err
 	}
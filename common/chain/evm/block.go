@@ -0,0 +1,122 @@
+package evm
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// 完整区块体积较大，单独放宽超时
+const blockTimeout = 15 * time.Second
+
+// Block 扫描用的区块，只保留入账识别需要的字段
+type Block struct {
+	Number       uint64
+	Hash         string
+	ParentHash   string
+	Timestamp    uint64
+	Transactions []Transaction
+}
+
+// Transaction 区块内交易，To 为空表示合约创建，地址均为小写
+type Transaction struct {
+	Hash  string
+	From  string
+	To    string
+	Value *big.Int
+}
+
+type rpcBlock struct {
+	Number       hexutil.Uint64 `json:"number"`
+	Hash         string         `json:"hash"`
+	ParentHash   string         `json:"parentHash"`
+	Timestamp    hexutil.Uint64 `json:"timestamp"`
+	Transactions []struct {
+		Hash  string       `json:"hash"`
+		From  string       `json:"from"`
+		To    *string      `json:"to"`
+		Value *hexutil.Big `json:"value"`
+	} `json:"transactions"`
+}
+
+// BlockNumber 最新区块高度
+func (c *EVMClient) BlockNumber(ctx context.Context, network string) (uint64, error) {
+	rc, err := c.pick(network)
+	if err != nil {
+		return 0, err
+	}
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+
+	var n hexutil.Uint64
+	if err := rc.CallContext(ctx2, &n, "eth_blockNumber"); err != nil {
+		return 0, err
+	}
+	return uint64(n), nil
+}
+
+// BlockByNumber 读取包含完整交易的区块
+func (c *EVMClient) BlockByNumber(ctx context.Context, network string, number uint64) (*Block, error) {
+	rc, err := c.pick(network)
+	if err != nil {
+		return nil, err
+	}
+	ctx2, cancel := context.WithTimeout(ctx, blockTimeout)
+	defer cancel()
+
+	var raw *rpcBlock
+	if err := rc.CallContext(ctx2, &raw, "eth_getBlockByNumber", hexutil.EncodeUint64(number), true); err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("block %d not found on %s", number, network)
+	}
+
+	b := &Block{
+		Number:       uint64(raw.Number),
+		Hash:         raw.Hash,
+		ParentHash:   raw.ParentHash,
+		Timestamp:    uint64(raw.Timestamp),
+		Transactions: make([]Transaction, 0, len(raw.Transactions)),
+	}
+	for _, t := range raw.Transactions {
+		tx := Transaction{
+			Hash:  t.Hash,
+			From:  strings.ToLower(t.From),
+			Value: new(big.Int),
+		}
+		if t.To != nil {
+			tx.To = strings.ToLower(*t.To)
+		}
+		if t.Value != nil {
+			tx.Value = t.Value.ToInt()
+		}
+		b.Transactions = append(b.Transactions, tx)
+	}
+	return b, nil
+}
+
+// BlockHash 区块哈希，不读取交易，用于链重组时比对
+func (c *EVMClient) BlockHash(ctx context.Context, network string, number uint64) (string, error) {
+	rc, err := c.pick(network)
+	if err != nil {
+		return "", err
+	}
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+
+	var head *struct {
+		Hash string `json:"hash"`
+	}
+	if err := rc.CallContext(ctx2, &head, "eth_getBlockByNumber", hexutil.EncodeUint64(number), false); err != nil {
+		return "", err
+	}
+	if head == nil {
+		return "", fmt.Errorf("block %d not found on %s", number, network)
+	}
+	return head.Hash, nil
+}
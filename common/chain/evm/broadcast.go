@@ -0,0 +1,58 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/reguluswee/walletus/common/chain/dep"
+)
+
+// Broadcast 通过 eth_sendRawTransaction 广播已签名交易
+// 交易哈希在本地计算，节点报 already known 或 nonce too low 但交易已上链时返回 ErrAlreadyKnown
+func (c *EVMClient) Broadcast(ctx context.Context, network string, rawTx []byte) (string, error) {
+	var tx types.Transaction
+	if err := tx.UnmarshalBinary(rawTx); err != nil {
+		return "", fmt.Errorf("decode raw tx: %w", err)
+	}
+	txHash := tx.Hash().Hex()
+
+	rc, err := c.pick(network)
+	if err != nil {
+		return "", err
+	}
+
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+
+	var result string
+	if err := rc.CallContext(ctx2, &result, "eth_sendRawTransaction", hexutil.Encode(rawTx)); err != nil {
+		be := dep.ClassifyBroadcastError(err.Error())
+		if errors.Is(be, dep.ErrNonceTooLow) && c.txKnown(ctx, network, txHash) {
+			// 同一笔交易已被打包，nonce 自然已被消耗
+			be.Kind = dep.ErrAlreadyKnown
+		}
+		return txHash, be
+	}
+	return txHash, nil
+}
+
+// txKnown 判断交易是否已在节点的交易池或链上
+func (c *EVMClient) txKnown(ctx context.Context, network, txHash string) bool {
+	rc, err := c.pick(network)
+	if err != nil {
+		return false
+	}
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+
+	var tx map[string]any
+	if err := rc.CallContext(ctx2, &tx, "eth_getTransactionByHash", txHash); err != nil {
+		return false
+	}
+	return tx != nil
+}
+
+var _ dep.Broadcaster = (*EVMClient)(nil)
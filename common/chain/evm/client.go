@@ -12,13 +12,16 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	gethrpc "github.com/ethereum/go-ethereum/rpc"
 	"github.com/reguluswee/walletus/common/chain/dep"
+	"github.com/reguluswee/walletus/common/chain/provider"
 	"golang.org/x/sync/singleflight"
 )
 
 // ABI 常量定义
 const erc20ABIJSON = `[
   {"constant":true,"inputs":[{"name":"owner","type":"address"}],
-   "name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"type":"function"}
+   "name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"type":"function"},
+  {"constant":false,"inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],
+   "name":"transfer","outputs":[{"name":"","type":"bool"}],"type":"function"}
 ]`
 
 // Multicall2.tryAggregate(bool,(address,bytes)[]) -> (bool,bytes)[]
@@ -36,15 +39,11 @@ const multicallABI = `[
       "internalType":"struct Multicall2.Result[]","name":"returnData","type":"tuple[]"}],
    "stateMutability":"nonpayable","type":"function"}]`
 
-type rpcPool struct {
-	clients []*gethrpc.Client
-	names   []string
-}
-
 type EVMClient struct {
 	mu           sync.RWMutex
-	pools        map[string]*rpcPool // network -> pool
-	mcAddr       map[string]string   // network -> multicall addr
+	pools        map[string]*rpcClient // network -> pool
+	mcAddr       map[string]string     // network -> multicall addr
+	chainIDs     map[string]*big.Int   // network -> chain id
 	sf           singleflight.Group
 	maxBatch     int
 	reqTimeout   time.Duration
@@ -56,8 +55,9 @@ type EVMClient struct {
 // RPC 配置会在首次使用时从系统配置中加载（通过 ensurePool 方法）
 func NewEVMClient(chain dep.ChainDef) *EVMClient {
 	iface := &EVMClient{
-		pools:      make(map[string]*rpcPool),
+		pools:      make(map[string]*rpcClient),
 		mcAddr:     make(map[string]string),
+		chainIDs:   make(map[string]*big.Int),
 		maxBatch:   256,             // 默认批处理大小
 		reqTimeout: 2 * time.Second, // 默认请求超时时间
 	}
@@ -84,13 +84,14 @@ func (c *EVMClient) Anchor(ctx context.Context, network string, cs dep.Consisten
 	if tag == "" {
 		tag = "latest"
 	}
-	rc, name, err := c.pick(network)
+	rc, err := c.pick(network)
 	if err != nil {
 		return dep.AnchorRef{}, err
 	}
 
 	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
 	defer cancel()
+	ctx2, served := provider.WithServed(ctx2)
 
 	var hexBlock string
 	switch tag {
@@ -123,7 +124,7 @@ func (c *EVMClient) Anchor(ctx context.Context, network string, cs dep.Consisten
 		Height:   height,
 		Tag:      cs.Mode,
 		Network:  network,
-		Provider: name,
+		Provider: *served,
 	}, nil
 }
 
@@ -149,7 +150,7 @@ func (c *EVMClient) NativeBalanceBatch(ctx context.Context, network string, addr
 	}
 	key := fmt.Sprintf("nb:%s:%d:%s", network, a.Height, hashStrings(addrs))
 	v, err, _ := c.sf.Do(key, func() (interface{}, error) {
-		rc, _, err := c.pick(network)
+		rc, err := c.pick(network)
 		if err != nil {
 			return nil, err
 		}
@@ -212,26 +213,6 @@ func (c *EVMClient) TokenBalancesBatch(ctx context.Context, network string, addr
 	return v.(map[string][]dep.TokenBalance), nil
 }
 
-func (c *EVMClient) GetTransaction(ctx context.Context, network string, txHash string) (any, error) {
-	rc, _, err := c.pick(network)
-	if err != nil {
-		return nil, err
-	}
-
-	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
-	defer cancel()
-
-	var receipt any
-
-	if err := rc.CallContext(ctx2, &receipt, "eth_getTransactionReceipt", txHash); err != nil {
-		return nil, err
-	}
-	if receipt == nil {
-		return nil, nil
-	}
-	return receipt, nil
-}
-
 // 注册（在 main 或 init 中）
 func MustRegister() {
 	for _, v := range dep.GetSupportedEVMs() {
@@ -0,0 +1,148 @@
+package evm
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/reguluswee/walletus/common/chain/dep"
+)
+
+const (
+	feeHistoryBlocks = 20
+	// 未提供 from/to 时 ERC-20 transfer 的默认 gas
+	defaultTokenTransferGas = 65000
+)
+
+var (
+	feeTierLevels      = []string{"slow", "standard", "fast"}
+	feeTierPercentiles = []float64{25, 50, 90}
+	// 不支持 EIP-1559 的链按 gasPrice 上浮比例分档
+	legacyTierPct = []int64{100, 110, 125}
+)
+
+// EstimateFee 基于 eth_feeHistory 返回下一区块的 base fee 及三档小费
+// 不支持 EIP-1559 的链退化为 eth_gasPrice 分档
+func (c *EVMClient) EstimateFee(ctx context.Context, network string, q dep.FeeQuery) (*dep.FeeEstimate, error) {
+	rc, err := c.pick(network)
+	if err != nil {
+		return nil, err
+	}
+	gasLimit, err := c.transferGas(ctx, rc, q)
+	if err != nil {
+		return nil, err
+	}
+	out := &dep.FeeEstimate{Symbol: nativeSymbol(network), Decimals: 18, GasLimit: gasLimit}
+	gas := new(big.Int).SetUint64(gasLimit)
+
+	baseFee, tips, err := c.feeHistory(ctx, rc)
+	if err != nil {
+		return nil, err
+	}
+	if baseFee == nil {
+		price, err := c.gasPrice(ctx, rc)
+		if err != nil {
+			return nil, err
+		}
+		out.BaseFee = price
+		for i, level := range feeTierLevels {
+			p := new(big.Int).Div(new(big.Int).Mul(price, big.NewInt(legacyTierPct[i])), big.NewInt(100))
+			out.Tiers = append(out.Tiers, dep.FeeTier{
+				Level:       level,
+				PriorityFee: big.NewInt(0),
+				MaxFee:      p,
+				Fee:         new(big.Int).Mul(p, gas),
+			})
+		}
+		return out, nil
+	}
+
+	out.BaseFee = baseFee
+	for i, level := range feeTierLevels {
+		tip := tips[i]
+		maxFee := new(big.Int).Add(new(big.Int).Mul(baseFee, big.NewInt(2)), tip)
+		out.Tiers = append(out.Tiers, dep.FeeTier{
+			Level:       level,
+			PriorityFee: tip,
+			MaxFee:      maxFee,
+			Fee:         new(big.Int).Mul(new(big.Int).Add(baseFee, tip), gas),
+		})
+	}
+	return out, nil
+}
+
+// feeHistory 返回下一区块的 base fee 和各分位的平均小费，链不支持 EIP-1559 时 baseFee 为 nil
+func (c *EVMClient) feeHistory(ctx context.Context, rc *rpcClient) (*big.Int, []*big.Int, error) {
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+	var result struct {
+		BaseFee []*hexutil.Big   `json:"baseFeePerGas"`
+		Reward  [][]*hexutil.Big `json:"reward"`
+	}
+	if err := rc.CallContext(ctx2, &result, "eth_feeHistory", hexutil.Uint64(feeHistoryBlocks), "latest", feeTierPercentiles); err != nil {
+		// 部分节点未实现 eth_feeHistory，按不支持 EIP-1559 处理；超时等其他错误照常返回
+		if isMethodNotFound(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	if len(result.BaseFee) == 0 || (*big.Int)(result.BaseFee[len(result.BaseFee)-1]).Sign() == 0 {
+		return nil, nil, nil
+	}
+	baseFee := (*big.Int)(result.BaseFee[len(result.BaseFee)-1])
+	return baseFee, averageRewards(result.Reward, len(feeTierPercentiles)), nil
+}
+
+// averageRewards 按分位对各区块的小费取平均，没有数据时使用默认小费
+func averageRewards(rewards [][]*hexutil.Big, n int) []*big.Int {
+	out := make([]*big.Int, n)
+	for i := 0; i < n; i++ {
+		sum := new(big.Int)
+		count := int64(0)
+		for _, r := range rewards {
+			if i < len(r) && r[i] != nil {
+				sum.Add(sum, (*big.Int)(r[i]))
+				count++
+			}
+		}
+		if count == 0 {
+			out[i] = big.NewInt(defaultPriorityFee)
+			continue
+		}
+		out[i] = sum.Div(sum, big.NewInt(count))
+	}
+	return out
+}
+
+// transferGas 原生币转账固定 21000，代币转账在提供 from/to 时按实际调用估算
+func (c *EVMClient) transferGas(ctx context.Context, rc *rpcClient, q dep.FeeQuery) (uint64, error) {
+	if q.Token == "" {
+		return nativeTransferGas, nil
+	}
+	if !common.IsHexAddress(q.Token) {
+		return 0, dep.ErrInvalidAddress
+	}
+	if q.From == "" || q.To == "" {
+		return defaultTokenTransferGas, nil
+	}
+	if !common.IsHexAddress(q.From) || !common.IsHexAddress(q.To) {
+		return 0, dep.ErrInvalidAddress
+	}
+	amount := big.NewInt(0)
+	if q.Amount != "" {
+		v, err := dep.ParseAmount(q.Amount)
+		if err != nil {
+			return 0, err
+		}
+		amount = v
+	}
+	data, err := c.erc20ABI.Pack("transfer", common.HexToAddress(q.To), amount)
+	if err != nil {
+		return 0, fmt.Errorf("pack transfer: %w", err)
+	}
+	return c.estimateGas(ctx, rc, common.HexToAddress(q.From), common.HexToAddress(q.Token), big.NewInt(0), data)
+}
+
+var _ dep.FeeEstimator = (*EVMClient)(nil)
@@ -0,0 +1,51 @@
+package evm
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/reguluswee/walletus/common/chain/dep"
+)
+
+// QuoteTokenTransferGas 按签名时相同的规则计算 ERC-20 转账所需原生币：gasLimit * feeCap（或 gasPrice）
+func (c *EVMClient) QuoteTokenTransferGas(ctx context.Context, network, token, from, to string, amount string) (*big.Int, error) {
+	if !common.IsHexAddress(token) || !common.IsHexAddress(from) || !common.IsHexAddress(to) {
+		return nil, dep.ErrInvalidAddress
+	}
+	value, err := dep.ParseAmount(amount)
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.erc20ABI.Pack("transfer", common.HexToAddress(to), value)
+	if err != nil {
+		return nil, fmt.Errorf("pack transfer: %w", err)
+	}
+
+	rc, err := c.pick(network)
+	if err != nil {
+		return nil, err
+	}
+	gasLimit, err := c.estimateGas(ctx, rc, common.HexToAddress(from), common.HexToAddress(token), big.NewInt(0), data)
+	if err != nil {
+		return nil, err
+	}
+
+	baseFee, err := c.latestBaseFee(ctx, rc)
+	if err != nil {
+		return nil, err
+	}
+	var perGas *big.Int
+	if baseFee != nil {
+		tip := c.suggestTip(ctx, rc)
+		perGas = new(big.Int).Add(new(big.Int).Mul(baseFee, big.NewInt(2)), tip)
+	} else {
+		if perGas, err = c.gasPrice(ctx, rc); err != nil {
+			return nil, err
+		}
+	}
+	return new(big.Int).Mul(perGas, new(big.Int).SetUint64(gasLimit)), nil
+}
+
+var _ dep.GasQuoter = (*EVMClient)(nil)
@@ -0,0 +1,143 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrLogRangeTooLarge 节点拒绝返回该区间的日志（结果过多或区间过大），调用方应缩小区间重试
+var ErrLogRangeTooLarge = errors.New("log query range too large")
+
+// transferTopic Transfer(address,address,uint256)
+var transferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// 各家节点对日志查询超限的报错
+var tooManyResultsKeywords = []string{
+	"too many results",
+	"query returned more than",
+	"exceed maximum block range",
+	"block range is too large",
+	"block range too large",
+	"range limit exceeded",
+	"response size exceeded",
+	"query timeout exceeded",
+}
+
+// TransferLog ERC-20 Transfer 事件，地址均为小写
+type TransferLog struct {
+	Token       string
+	From        string
+	To          string
+	Amount      *big.Int
+	TxHash      string
+	LogIndex    uint
+	BlockNumber uint64
+	BlockHash   string
+}
+
+type rpcLog struct {
+	Address     string         `json:"address"`
+	Topics      []string       `json:"topics"`
+	Data        string         `json:"data"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	BlockHash   string         `json:"blockHash"`
+	TxHash      string         `json:"transactionHash"`
+	LogIndex    hexutil.Uint   `json:"logIndex"`
+	Removed     bool           `json:"removed"`
+}
+
+// TransferLogs 通过 eth_getLogs 读取 tokens 在 [from, to] 内的 Transfer 事件
+// 节点因区间过大拒绝时返回 ErrLogRangeTooLarge
+func (c *EVMClient) TransferLogs(ctx context.Context, network string, tokens []string, from, to uint64) ([]TransferLog, error) {
+	return c.TransferLogsTo(ctx, network, tokens, "", from, to)
+}
+
+// TransferLogsTo 同 TransferLogs，recipient 非空时只返回转入该地址的事件，由节点按 topic 过滤
+func (c *EVMClient) TransferLogsTo(ctx context.Context, network string, tokens []string, recipient string, from, to uint64) ([]TransferLog, error) {
+	rc, err := c.pick(network)
+	if err != nil {
+		return nil, err
+	}
+	ctx2, cancel := context.WithTimeout(ctx, blockTimeout)
+	defer cancel()
+
+	addrs := make([]common.Address, 0, len(tokens))
+	for _, t := range tokens {
+		if !common.IsHexAddress(t) {
+			return nil, fmt.Errorf("invalid token address: %s", t)
+		}
+		addrs = append(addrs, common.HexToAddress(t))
+	}
+	topics := []any{transferTopic}
+	if recipient != "" {
+		if !common.IsHexAddress(recipient) {
+			return nil, fmt.Errorf("invalid recipient address: %s", recipient)
+		}
+		topics = append(topics, nil, common.BytesToHash(common.HexToAddress(recipient).Bytes()))
+	}
+	filter := map[string]any{
+		"fromBlock": hexutil.EncodeUint64(from),
+		"toBlock":   hexutil.EncodeUint64(to),
+		"address":   addrs,
+		"topics":    topics,
+	}
+
+	var raw []rpcLog
+	if err := rc.CallContext(ctx2, &raw, "eth_getLogs", filter); err != nil {
+		if isTooManyResults(err) {
+			return nil, fmt.Errorf("%w: %v", ErrLogRangeTooLarge, err)
+		}
+		return nil, err
+	}
+
+	out := make([]TransferLog, 0, len(raw))
+	for _, l := range raw {
+		if tl, ok := decodeTransferLog(l); ok {
+			out = append(out, tl)
+		}
+	}
+	return out, nil
+}
+
+// decodeTransferLog 只接受标准 ERC-20 Transfer（from、to 为 indexed，value 在 data 中）
+// ERC-721 的 Transfer 有 4 个 topic，在这里被排除
+func decodeTransferLog(l rpcLog) (TransferLog, bool) {
+	if l.Removed || len(l.Topics) != 3 || !strings.EqualFold(l.Topics[0], transferTopic.Hex()) {
+		return TransferLog{}, false
+	}
+	data, err := hexutil.Decode(l.Data)
+	if err != nil || len(data) != 32 {
+		return TransferLog{}, false
+	}
+	return TransferLog{
+		Token:       strings.ToLower(l.Address),
+		From:        topicAddress(l.Topics[1]),
+		To:          topicAddress(l.Topics[2]),
+		Amount:      new(big.Int).SetBytes(data),
+		TxHash:      l.TxHash,
+		LogIndex:    uint(l.LogIndex),
+		BlockNumber: uint64(l.BlockNumber),
+		BlockHash:   l.BlockHash,
+	}, true
+}
+
+func topicAddress(topic string) string {
+	return strings.ToLower(common.BytesToAddress(common.FromHex(topic)).Hex())
+}
+
+func isTooManyResults(err error) bool {
+	lower := strings.ToLower(err.Error())
+	for _, k := range tooManyResultsKeywords {
+		if strings.Contains(lower, k) {
+			return true
+		}
+	}
+	return false
+}
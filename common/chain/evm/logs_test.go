@@ -0,0 +1,61 @@
+package evm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecodeTransferLog(t *testing.T) {
+	l := rpcLog{
+		Address: "0x55d398326f99059fF775485246999027B3197955",
+		Topics: []string{
+			transferTopic.Hex(),
+			"0x000000000000000000000000aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+			"0x000000000000000000000000BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB",
+		},
+		Data:        "0x00000000000000000000000000000000000000000000000000000000000f4240",
+		BlockNumber: 10,
+		LogIndex:    3,
+	}
+	tl, ok := decodeTransferLog(l)
+	if !ok {
+		t.Fatal("expected transfer log")
+	}
+	if tl.Token != "0x55d398326f99059ff775485246999027b3197955" {
+		t.Fatalf("token = %s", tl.Token)
+	}
+	if tl.From != "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa" || tl.To != "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb" {
+		t.Fatalf("from/to = %s %s", tl.From, tl.To)
+	}
+	if tl.Amount.Int64() != 1_000_000 || tl.LogIndex != 3 || tl.BlockNumber != 10 {
+		t.Fatalf("unexpected log %+v", tl)
+	}
+
+	// ERC-721 Transfer: tokenId 作为第 4 个 topic
+	nft := l
+	nft.Topics = append(append([]string{}, l.Topics...), "0x01")
+	nft.Data = "0x"
+	if _, ok := decodeTransferLog(nft); ok {
+		t.Fatal("erc721 transfer should be skipped")
+	}
+
+	removed := l
+	removed.Removed = true
+	if _, ok := decodeTransferLog(removed); ok {
+		t.Fatal("removed log should be skipped")
+	}
+}
+
+func TestIsTooManyResults(t *testing.T) {
+	cases := map[string]bool{
+		"query returned more than 10000 results":         true,
+		"eth_getLogs block range is too large, max 5000": true,
+		"Log response size exceeded":                     true,
+		"execution reverted":                             false,
+	}
+	for msg, want := range cases {
+		if got := isTooManyResults(errors.New(msg)); got != want {
+			t.Errorf("%q: got %v want %v", msg, got, want)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package evm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/reguluswee/walletus/common/chain/dep"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	NonceStatusReserved = "reserved" // 已预留，等待签名广播
+	NonceStatusUsed     = "used"     // 已广播
+	NonceStatusReleased = "released" // 签名或广播失败，可被复用
+	NonceStatusDone     = "done"     // 链上 pending nonce 已越过
+
+	// 预留后未提交的租期，超时视为持有方已退出
+	defaultNonceLease = 2 * time.Minute
+	// 已广播但始终未进入节点交易池的判定时间，超时视为被丢弃
+	defaultNonceDrop = 10 * time.Minute
+)
+
+var ErrNonceReservation = errors.New("nonce reservation not active")
+
+type evmNonce struct {
+	Network    string    `gorm:"column:network;type:varchar(50);primaryKey"`
+	Address    string    `gorm:"column:address;type:varchar(42);primaryKey"`
+	NextNonce  uint64    `gorm:"column:next_nonce;not null;default:0"`
+	AddTime    time.Time `gorm:"column:add_time"`
+	UpdateTime time.Time `gorm:"column:update_time"`
+}
+
+func (evmNonce) TableName() string { return "evm_nonce" }
+
+type evmNonceReservation struct {
+	ID         uint64    `gorm:"column:id;primaryKey;autoIncrement"`
+	Network    string    `gorm:"column:network;type:varchar(50);not null"`
+	Address    string    `gorm:"column:address;type:varchar(42);not null"`
+	Nonce      uint64    `gorm:"column:nonce;not null"`
+	Status     string    `gorm:"column:status;type:varchar(20);not null"`
+	TxHash     string    `gorm:"column:tx_hash;type:varchar(66);not null"`
+	AddTime    time.Time `gorm:"column:add_time"`
+	UpdateTime time.Time `gorm:"column:update_time"`
+}
+
+func (evmNonceReservation) TableName() string { return "evm_nonce_reservation" }
+
+// NonceSource 提供链上 pending nonce
+type NonceSource interface {
+	PendingNonce(ctx context.Context, network, address string) (uint64, error)
+}
+
+// NonceReservation 一次 nonce 预留，签名广播后需 Commit 或 Release
+type NonceReservation struct {
+	ID      uint64
+	Network string
+	Address string
+	Nonce   uint64
+}
+
+// NonceManager 以数据库行锁为 (network, address) 分配 nonce，多个 modapi 实例共享同一序列
+// 每次预留都会与链上 pending nonce 对齐：低于 pending 的预留标记完成，
+// 高于 pending 但已释放、租期过期或被丢弃的 nonce 视为空洞优先复用
+type NonceManager struct {
+	db     *gorm.DB
+	source NonceSource
+	lease  time.Duration
+	drop   time.Duration
+}
+
+func NewNonceManager(db *gorm.DB, source NonceSource) *NonceManager {
+	return &NonceManager{db: db, source: source, lease: defaultNonceLease, drop: defaultNonceDrop}
+}
+
+// PendingNonce 返回地址在链上的 pending nonce
+func (c *EVMClient) PendingNonce(ctx context.Context, network, address string) (uint64, error) {
+	if !common.IsHexAddress(address) {
+		return 0, dep.ErrInvalidAddress
+	}
+	rc, err := c.pick(network)
+	if err != nil {
+		return 0, err
+	}
+	return c.pendingNonce(ctx, rc, common.HexToAddress(address))
+}
+
+// Reserve 为地址预留下一个可用 nonce
+func (m *NonceManager) Reserve(ctx context.Context, network, address string) (*NonceReservation, error) {
+	addr, err := normalizeNonceAddr(address)
+	if err != nil {
+		return nil, err
+	}
+	chainNonce, err := m.source.PendingNonce(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	var out *NonceReservation
+	err = m.withLock(ctx, network, addr, chainNonce, func(tx *gorm.DB, row *evmNonce, live map[uint64]*evmNonceReservation) error {
+		now := time.Now()
+		nonce, reuse := pickNonce(chainNonce, row.NextNonce, func(n uint64) bool { return m.isLive(live[n], now) })
+		rec := live[nonce]
+		if reuse && rec != nil {
+			rec.Status = NonceStatusReserved
+			rec.TxHash = ""
+			rec.AddTime = now
+			rec.UpdateTime = now
+			if err := tx.Save(rec).Error; err != nil {
+				return err
+			}
+		} else {
+			rec = &evmNonceReservation{Network: network, Address: addr, Nonce: nonce, Status: NonceStatusReserved, AddTime: now, UpdateTime: now}
+			if err := tx.Create(rec).Error; err != nil {
+				return err
+			}
+		}
+		if nonce >= row.NextNonce {
+			row.NextNonce = nonce + 1
+		}
+		row.UpdateTime = now
+		if err := tx.Save(row).Error; err != nil {
+			return err
+		}
+		out = &NonceReservation{ID: rec.ID, Network: network, Address: addr, Nonce: nonce}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Commit 标记预留的 nonce 已随交易广播
+func (m *NonceManager) Commit(ctx context.Context, r *NonceReservation, txHash string) error {
+	res := m.db.WithContext(ctx).Model(&evmNonceReservation{}).
+		Where("id = ? and nonce = ? and status = ?", r.ID, r.Nonce, NonceStatusReserved).
+		Updates(map[string]interface{}{"status": NonceStatusUsed, "tx_hash": txHash, "update_time": time.Now()})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrNonceReservation
+	}
+	return nil
+}
+
+// Release 签名或广播失败时归还 nonce，下次预留时优先复用
+func (m *NonceManager) Release(ctx context.Context, r *NonceReservation) error {
+	return m.db.WithContext(ctx).Model(&evmNonceReservation{}).
+		Where("id = ? and nonce = ? and status in ?", r.ID, r.Nonce, []string{NonceStatusReserved, NonceStatusUsed}).
+		Updates(map[string]interface{}{"status": NonceStatusReleased, "update_time": time.Now()}).Error
+}
+
+// Sync 将地址的 nonce 序列与链上 pending nonce 对齐，用于启动时校准
+func (m *NonceManager) Sync(ctx context.Context, network, address string) error {
+	addr, err := normalizeNonceAddr(address)
+	if err != nil {
+		return err
+	}
+	chainNonce, err := m.source.PendingNonce(ctx, network, addr)
+	if err != nil {
+		return err
+	}
+	return m.withLock(ctx, network, addr, chainNonce, func(tx *gorm.DB, row *evmNonce, live map[uint64]*evmNonceReservation) error {
+		row.UpdateTime = time.Now()
+		return tx.Save(row).Error
+	})
+}
+
+// SyncAll 校准所有已登记的地址，单个地址失败不影响其他地址
+func (m *NonceManager) SyncAll(ctx context.Context) error {
+	var rows []evmNonce
+	if err := m.db.WithContext(ctx).Find(&rows).Error; err != nil {
+		return err
+	}
+	var errs []error
+	for _, r := range rows {
+		if err := m.Sync(ctx, r.Network, r.Address); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", r.Network, r.Address, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// withLock 锁定 (network, address) 行并完成与链上 nonce 的对齐，再执行 fn
+// live 为 [chainNonce, next_nonce) 区间内的预留记录
+func (m *NonceManager) withLock(ctx context.Context, network, addr string, chainNonce uint64, fn func(tx *gorm.DB, row *evmNonce, live map[uint64]*evmNonceReservation) error) error {
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		seed := evmNonce{Network: network, Address: addr, NextNonce: chainNonce, AddTime: now, UpdateTime: now}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&seed).Error; err != nil {
+			return err
+		}
+		var row evmNonce
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("network = ? AND address = ?", network, addr).
+			Take(&row).Error; err != nil {
+			return err
+		}
+		// 外部发出的交易使链上 nonce 领先
+		if chainNonce > row.NextNonce {
+			row.NextNonce = chainNonce
+		}
+		if err := tx.Model(&evmNonceReservation{}).
+			Where("network = ? AND address = ? AND nonce < ? AND status in ?", network, addr, chainNonce,
+				[]string{NonceStatusReserved, NonceStatusUsed, NonceStatusReleased}).
+			Updates(map[string]interface{}{"status": NonceStatusDone, "update_time": now}).Error; err != nil {
+			return err
+		}
+
+		var recs []*evmNonceReservation
+		if err := tx.Where("network = ? AND address = ? AND nonce >= ? AND nonce < ?", network, addr, chainNonce, row.NextNonce).
+			Find(&recs).Error; err != nil {
+			return err
+		}
+		live := make(map[uint64]*evmNonceReservation, len(recs))
+		for _, r := range recs {
+			live[r.Nonce] = r
+		}
+		return fn(tx, &row, live)
+	}, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+}
+
+// isLive 预留仍被持有：在租期内未提交，或已广播且未超过丢弃判定时间
+func (m *NonceManager) isLive(r *evmNonceReservation, now time.Time) bool {
+	if r == nil {
+		return false
+	}
+	switch r.Status {
+	case NonceStatusReserved:
+		return now.Sub(r.AddTime) < m.lease
+	case NonceStatusUsed:
+		return now.Sub(r.UpdateTime) < m.drop
+	}
+	return false
+}
+
+// pickNonce 在 [chainNonce, next) 中选择最小的空洞，没有空洞时取 max(next, chainNonce)
+func pickNonce(chainNonce, next uint64, live func(uint64) bool) (uint64, bool) {
+	for n := chainNonce; n < next; n++ {
+		if !live(n) {
+			return n, true
+		}
+	}
+	return max(next, chainNonce), false
+}
+
+func normalizeNonceAddr(address string) (string, error) {
+	if !common.IsHexAddress(address) {
+		return "", dep.ErrInvalidAddress
+	}
+	return strings.ToLower(common.HexToAddress(address).Hex()), nil
+}
+
+var _ NonceSource = (*EVMClient)(nil)
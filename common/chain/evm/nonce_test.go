@@ -0,0 +1,46 @@
+package evm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPickNonce(t *testing.T) {
+	live := map[uint64]bool{5: true, 6: true, 8: true}
+	isLive := func(n uint64) bool { return live[n] }
+
+	// 7 的交易被丢弃，优先补上空洞
+	if n, reuse := pickNonce(5, 9, isLive); n != 7 || !reuse {
+		t.Fatalf("expected gap 7, got %d %v", n, reuse)
+	}
+	// 无空洞时顺延
+	live[7] = true
+	if n, reuse := pickNonce(5, 9, isLive); n != 9 || reuse {
+		t.Fatalf("expected 9, got %d %v", n, reuse)
+	}
+	// 链上 nonce 领先于本地记录
+	if n, _ := pickNonce(12, 9, isLive); n != 12 {
+		t.Fatalf("expected 12, got %d", n)
+	}
+}
+
+func TestNonceLiveness(t *testing.T) {
+	m := &NonceManager{lease: time.Minute, drop: 10 * time.Minute}
+	now := time.Now()
+	cases := []struct {
+		r    *evmNonceReservation
+		live bool
+	}{
+		{nil, false},
+		{&evmNonceReservation{Status: NonceStatusReserved, AddTime: now.Add(-30 * time.Second)}, true},
+		{&evmNonceReservation{Status: NonceStatusReserved, AddTime: now.Add(-2 * time.Minute)}, false},
+		{&evmNonceReservation{Status: NonceStatusUsed, UpdateTime: now.Add(-5 * time.Minute)}, true},
+		{&evmNonceReservation{Status: NonceStatusUsed, UpdateTime: now.Add(-11 * time.Minute)}, false},
+		{&evmNonceReservation{Status: NonceStatusReleased, UpdateTime: now}, false},
+	}
+	for i, c := range cases {
+		if got := m.isLive(c.r, now); got != c.live {
+			t.Fatalf("case %d: expected %v, got %v", i, c.live, got)
+		}
+	}
+}
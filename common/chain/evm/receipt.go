@@ -0,0 +1,98 @@
+package evm
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/reguluswee/walletus/common/chain/dep"
+)
+
+type rpcTransaction struct {
+	Hash  string       `json:"hash"`
+	From  string       `json:"from"`
+	To    *string      `json:"to"`
+	Value *hexutil.Big `json:"value"`
+}
+
+type rpcReceipt struct {
+	Status            hexutil.Uint64 `json:"status"`
+	BlockNumber       hexutil.Uint64 `json:"blockNumber"`
+	GasUsed           *hexutil.Big   `json:"gasUsed"`
+	EffectiveGasPrice *hexutil.Big   `json:"effectiveGasPrice"`
+	L1Fee             *hexutil.Big   `json:"l1Fee"` // OP 系 L2 额外收取的 L1 数据费
+	Logs              []rpcLog       `json:"logs"`
+}
+
+// GetTransaction 在一次批量请求中读取交易与回执
+// 有交易无回执为 pending；手续费为 gasUsed * effectiveGasPrice，OP 系 L2 另加 l1Fee
+func (c *EVMClient) GetTransaction(ctx context.Context, network string, txHash string, anchor dep.AnchorRef) (*dep.TxReceipt, error) {
+	rc, err := c.pick(network)
+	if err != nil {
+		return nil, err
+	}
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+
+	var tx *rpcTransaction
+	var receipt *rpcReceipt
+	elems := []gethrpc.BatchElem{
+		{Method: "eth_getTransactionByHash", Args: []any{txHash}, Result: &tx},
+		{Method: "eth_getTransactionReceipt", Args: []any{txHash}, Result: &receipt},
+	}
+	if err := rc.BatchCallContext(ctx2, elems); err != nil {
+		return nil, err
+	}
+	for _, e := range elems {
+		if e.Error != nil {
+			return nil, e.Error
+		}
+	}
+
+	out := decodeReceipt(txHash, tx, receipt)
+	out.SetAnchor(anchor)
+	return out, nil
+}
+
+func decodeReceipt(txHash string, tx *rpcTransaction, receipt *rpcReceipt) *dep.TxReceipt {
+	out := &dep.TxReceipt{TxHash: txHash, Status: dep.TxStatusNotFound}
+	switch {
+	case receipt != nil:
+	case tx != nil:
+		out.Status = dep.TxStatusPending
+		return out
+	default:
+		return out
+	}
+
+	out.BlockHeight = uint64(receipt.BlockNumber)
+	out.Fee = new(big.Int)
+	if receipt.GasUsed != nil && receipt.EffectiveGasPrice != nil {
+		out.Fee.Mul(receipt.GasUsed.ToInt(), receipt.EffectiveGasPrice.ToInt())
+	}
+	if receipt.L1Fee != nil {
+		out.Fee.Add(out.Fee, receipt.L1Fee.ToInt())
+	}
+	if receipt.Status != 1 {
+		out.Status = dep.TxStatusFailed
+		out.Error = "execution reverted"
+		return out
+	}
+
+	out.Status = dep.TxStatusSuccess
+	if tx != nil && tx.To != nil && tx.Value != nil && tx.Value.ToInt().Sign() > 0 {
+		out.Transfers = append(out.Transfers, dep.TxTransfer{
+			From:   strings.ToLower(tx.From),
+			To:     strings.ToLower(*tx.To),
+			Amount: tx.Value.ToInt(),
+		})
+	}
+	for _, l := range receipt.Logs {
+		if tl, ok := decodeTransferLog(l); ok {
+			out.Transfers = append(out.Transfers, dep.TxTransfer{Token: tl.Token, From: tl.From, To: tl.To, Amount: tl.Amount})
+		}
+	}
+	return out
+}
@@ -0,0 +1,49 @@
+package evm
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/reguluswee/walletus/common/chain/dep"
+)
+
+func TestDecodeReceipt(t *testing.T) {
+	var tx *rpcTransaction
+	var receipt *rpcReceipt
+	if err := json.Unmarshal([]byte(`{"hash":"0x01","from":"0xAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA",
+		"to":"0x55d398326f99059fF775485246999027B3197955","value":"0x0"}`), &tx); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(`{"status":"0x1","blockNumber":"0x64","gasUsed":"0x5208","effectiveGasPrice":"0x3b9aca00",
+		"l1Fee":"0x10","logs":[{"address":"0x55d398326f99059fF775485246999027B3197955",
+		"topics":["`+transferTopic.Hex()+`",
+			"0x000000000000000000000000aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+			"0x000000000000000000000000bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"],
+		"data":"0x00000000000000000000000000000000000000000000000000000000000f4240"}]}`), &receipt); err != nil {
+		t.Fatal(err)
+	}
+
+	r := decodeReceipt("0x01", tx, receipt)
+	r.SetAnchor(dep.AnchorRef{Height: 111})
+	if r.Status != dep.TxStatusSuccess || r.BlockHeight != 100 || r.Confirmations != 12 {
+		t.Fatalf("unexpected receipt %+v", r)
+	}
+	// 21000 * 1 gwei + l1Fee
+	if r.Fee.String() != "21000000000016" {
+		t.Fatalf("fee = %s", r.Fee)
+	}
+	if len(r.Transfers) != 1 || r.Transfers[0].Amount.Int64() != 1_000_000 || r.Transfers[0].To != "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb" {
+		t.Fatalf("unexpected transfers %+v", r.Transfers)
+	}
+
+	receipt.Status = 0
+	if r = decodeReceipt("0x01", tx, receipt); r.Status != dep.TxStatusFailed || len(r.Transfers) != 0 {
+		t.Fatalf("reverted receipt %+v", r)
+	}
+	if r = decodeReceipt("0x01", tx, nil); r.Status != dep.TxStatusPending {
+		t.Fatalf("status = %s, want pending", r.Status)
+	}
+	if r = decodeReceipt("0x01", nil, nil); r.Status != dep.TxStatusNotFound {
+		t.Fatalf("status = %s, want not_found", r.Status)
+	}
+}
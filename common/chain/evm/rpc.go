@@ -0,0 +1,72 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/reguluswee/walletus/common/chain/provider"
+)
+
+const (
+	// 节点限流或资源超限
+	errCodeLimitExceeded = -32005
+	// 节点未实现该方法
+	errCodeMethodNotFound = -32601
+)
+
+// rpcClient 一条链上全部提供商的 JSON-RPC 客户端，方法与 gethrpc.Client 一致，每次调用经 provider.Pool 选择端点
+// 除 eth_sendRawTransaction 外均为幂等读取，端点故障时换一个端点重试
+type rpcClient struct {
+	pool *provider.Pool[*gethrpc.Client]
+}
+
+func (rc *rpcClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return rc.pool.Do(ctx, 1, idempotent(method), func(ctx context.Context, e *provider.Endpoint[*gethrpc.Client]) error {
+		return e.Client.CallContext(ctx, result, method, args...)
+	})
+}
+
+// BatchCallContext 每个元素按一次请求计入提供商配额；单个元素的错误记录在 BatchElem.Error 中，不影响端点健康
+func (rc *rpcClient) BatchCallContext(ctx context.Context, b []gethrpc.BatchElem) error {
+	retry := true
+	for _, e := range b {
+		retry = retry && idempotent(e.Method)
+	}
+	return rc.pool.Do(ctx, len(b), retry, func(ctx context.Context, e *provider.Endpoint[*gethrpc.Client]) error {
+		return e.Client.BatchCallContext(ctx, b)
+	})
+}
+
+func idempotent(method string) bool {
+	return method != "eth_sendRawTransaction"
+}
+
+// isMethodNotFound 节点未实现调用的方法，部分提供商不返回标准错误码，按 geth 与 JSON-RPC 规范的错误信息兜底
+func isMethodNotFound(err error) bool {
+	var re gethrpc.Error
+	if errors.As(err, &re) && re.ErrorCode() == errCodeMethodNotFound {
+		return true
+	}
+	lower := strings.ToLower(err.Error())
+	return strings.Contains(lower, "method not found") || strings.Contains(lower, "does not exist/is not available")
+}
+
+// isEndpointFailure 节点正常返回的 JSON-RPC 错误（revert、日志区间超限等）不归咎于端点，限流除外
+func isEndpointFailure(err error) bool {
+	if isTooManyResults(err) {
+		return false
+	}
+	var he gethrpc.HTTPError
+	if errors.As(err, &he) && he.StatusCode >= 400 && he.StatusCode < 500 {
+		return he.StatusCode == http.StatusTooManyRequests || he.StatusCode == http.StatusRequestTimeout ||
+			he.StatusCode == http.StatusUnauthorized || he.StatusCode == http.StatusForbidden
+	}
+	var re gethrpc.Error
+	if errors.As(err, &re) {
+		return re.ErrorCode() == errCodeLimitExceeded
+	}
+	return true
+}
@@ -0,0 +1,34 @@
+package evm
+
+import (
+	"errors"
+	"testing"
+)
+
+type rpcCodeError struct {
+	code int
+	msg  string
+}
+
+func (e rpcCodeError) Error() string  { return e.msg }
+func (e rpcCodeError) ErrorCode() int { return e.code }
+
+func TestIsMethodNotFound(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{rpcCodeError{code: errCodeMethodNotFound, msg: "unsupported"}, true},
+		{rpcCodeError{code: -32000, msg: "execution reverted"}, false},
+		{errors.New("the method eth_feeHistory does not exist/is not available"), true},
+		{errors.New("Method not found"), true},
+		{errors.New("transaction type not supported"), false},
+		{errors.New("context deadline exceeded"), false},
+		{errors.New("429 Too Many Requests: rate limit"), false},
+	}
+	for _, c := range cases {
+		if got := isMethodNotFound(c.err); got != c.want {
+			t.Errorf("%q: got %v want %v", c.err, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package evm
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/reguluswee/walletus/common/bip"
+	"github.com/reguluswee/walletus/common/chain/dep"
+)
+
+const (
+	nativeTransferGas = 21000
+	// 合约调用的 gas 估算余量（百分比）
+	gasLimitBufferPct = 20
+	// eth_maxPriorityFeePerGas 不可用时的默认小费 1 gwei
+	defaultPriorityFee = 1_000_000_000
+)
+
+// SignTransferNative 签名原生币转账（ETH/BNB/MATIC...）
+func (c *EVMClient) SignTransferNative(ctx context.Context, network string, from, to string, amount string, opts any) ([]byte, string, error) {
+	if !common.IsHexAddress(to) {
+		return nil, "", dep.ErrInvalidAddress
+	}
+	value, err := dep.ParseAmount(amount)
+	if err != nil {
+		return nil, "", err
+	}
+	return c.signTx(ctx, network, from, common.HexToAddress(to), value, nil, opts)
+}
+
+// SignTransferToken 签名 ERC-20 transfer(address,uint256) 调用
+func (c *EVMClient) SignTransferToken(ctx context.Context, network, token, from, to string, amount string, opts any) ([]byte, string, error) {
+	if !common.IsHexAddress(to) || !common.IsHexAddress(token) {
+		return nil, "", dep.ErrInvalidAddress
+	}
+	value, err := dep.ParseAmount(amount)
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := c.erc20ABI.Pack("transfer", common.HexToAddress(to), value)
+	if err != nil {
+		return nil, "", fmt.Errorf("pack transfer: %w", err)
+	}
+	return c.signTx(ctx, network, from, common.HexToAddress(token), big.NewInt(0), data, opts)
+}
+
+func (c *EVMClient) signTx(ctx context.Context, network, from string, to common.Address, value *big.Int, data []byte, opts any) ([]byte, string, error) {
+	o, err := dep.ToSignOptions(opts)
+	if err != nil {
+		return nil, "", err
+	}
+	if !common.IsHexAddress(from) {
+		return nil, "", dep.ErrInvalidAddress
+	}
+	fromAddr := common.HexToAddress(from)
+
+	priv, err := signingKey(network, fromAddr, o.Key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rc, err := c.pick(network)
+	if err != nil {
+		return nil, "", err
+	}
+
+	chainID, err := c.chainID(ctx, rc, network)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nonce uint64
+	if o.Nonce != nil {
+		nonce = *o.Nonce
+	} else {
+		if nonce, err = c.pendingNonce(ctx, rc, fromAddr); err != nil {
+			return nil, "", err
+		}
+	}
+
+	gasLimit := o.GasLimit
+	if gasLimit == 0 {
+		if gasLimit, err = c.estimateGas(ctx, rc, fromAddr, to, value, data); err != nil {
+			return nil, "", err
+		}
+	}
+
+	var txData types.TxData
+	baseFee, err := c.latestBaseFee(ctx, rc)
+	if err != nil {
+		return nil, "", err
+	}
+	if baseFee != nil && !o.Legacy {
+		tip := o.MaxPriorityFeePerGas
+		if tip == nil {
+			tip = c.suggestTip(ctx, rc)
+		}
+		feeCap := o.MaxFeePerGas
+		if feeCap == nil {
+			// 预留两个区块的 base fee 上涨空间
+			feeCap = new(big.Int).Add(new(big.Int).Mul(baseFee, big.NewInt(2)), tip)
+		}
+		if feeCap.Cmp(tip) < 0 {
+			return nil, "", fmt.Errorf("max fee per gas %s below priority fee %s", feeCap, tip)
+		}
+		txData = &types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     nonce,
+			GasTipCap: tip,
+			GasFeeCap: feeCap,
+			Gas:       gasLimit,
+			To:        &to,
+			Value:     value,
+			Data:      data,
+		}
+	} else {
+		gasPrice := o.GasPrice
+		if gasPrice == nil {
+			if gasPrice, err = c.gasPrice(ctx, rc); err != nil {
+				return nil, "", err
+			}
+		}
+		txData = &types.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: gasPrice,
+			Gas:      gasLimit,
+			To:       &to,
+			Value:    value,
+			Data:     data,
+		}
+	}
+
+	signed, err := types.SignTx(types.NewTx(txData), types.LatestSignerForChainID(chainID), priv)
+	if err != nil {
+		return nil, "", fmt.Errorf("sign tx: %w", err)
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return nil, "", fmt.Errorf("encode tx: %w", err)
+	}
+	return raw, signed.Hash().Hex(), nil
+}
+
+// signingKey 按派生路径取出私钥，并校验其地址与 from 一致
+func signingKey(network string, from common.Address, k dep.KeyRef) (*ecdsa.PrivateKey, error) {
+	enc, err := bip.EncMasterFromKeyRef(k)
+	if err != nil {
+		return nil, err
+	}
+	addr, priv, err := bip.AddressAndPrivFromPath(enc, k.Path, strings.ToUpper(network))
+	if err != nil {
+		return nil, err
+	}
+	if common.HexToAddress(addr) != from {
+		return nil, dep.ErrKeyMismatch
+	}
+	return priv, nil
+}
+
+func (c *EVMClient) chainID(ctx context.Context, rc *rpcClient, network string) (*big.Int, error) {
+	c.mu.RLock()
+	id, ok := c.chainIDs[network]
+	c.mu.RUnlock()
+	if ok {
+		return id, nil
+	}
+
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+	var result hexutil.Big
+	if err := rc.CallContext(ctx2, &result, "eth_chainId"); err != nil {
+		return nil, err
+	}
+	id = (*big.Int)(&result)
+
+	c.mu.Lock()
+	c.chainIDs[network] = id
+	c.mu.Unlock()
+	return id, nil
+}
+
+func (c *EVMClient) pendingNonce(ctx context.Context, rc *rpcClient, addr common.Address) (uint64, error) {
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+	var result hexutil.Uint64
+	if err := rc.CallContext(ctx2, &result, "eth_getTransactionCount", addr, "pending"); err != nil {
+		return 0, err
+	}
+	return uint64(result), nil
+}
+
+func (c *EVMClient) estimateGas(ctx context.Context, rc *rpcClient, from, to common.Address, value *big.Int, data []byte) (uint64, error) {
+	if len(data) == 0 {
+		return nativeTransferGas, nil
+	}
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+	msg := map[string]any{
+		"from":  from,
+		"to":    to,
+		"value": (*hexutil.Big)(value),
+		"data":  hexutil.Bytes(data),
+	}
+	var result hexutil.Uint64
+	if err := rc.CallContext(ctx2, &result, "eth_estimateGas", msg); err != nil {
+		return 0, fmt.Errorf("estimate gas: %w", err)
+	}
+	gas := uint64(result)
+	return gas + gas*gasLimitBufferPct/100, nil
+}
+
+// latestBaseFee 返回最新区块的 base fee，不支持 EIP-1559 的链返回 nil
+func (c *EVMClient) latestBaseFee(ctx context.Context, rc *rpcClient) (*big.Int, error) {
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+	var head struct {
+		BaseFee *hexutil.Big `json:"baseFeePerGas"`
+	}
+	if err := rc.CallContext(ctx2, &head, "eth_getBlockByNumber", "latest", false); err != nil {
+		return nil, err
+	}
+	if head.BaseFee == nil {
+		return nil, nil
+	}
+	return (*big.Int)(head.BaseFee), nil
+}
+
+func (c *EVMClient) suggestTip(ctx context.Context, rc *rpcClient) *big.Int {
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+	var result hexutil.Big
+	if err := rc.CallContext(ctx2, &result, "eth_maxPriorityFeePerGas"); err != nil {
+		// 部分节点未实现该方法，使用默认小费
+		return big.NewInt(defaultPriorityFee)
+	}
+	return (*big.Int)(&result)
+}
+
+func (c *EVMClient) gasPrice(ctx context.Context, rc *rpcClient) (*big.Int, error) {
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+	var result hexutil.Big
+	if err := rc.CallContext(ctx2, &result, "eth_gasPrice"); err != nil {
+		return nil, err
+	}
+	return (*big.Int)(&result), nil
+}
+
+var _ dep.Signer = (*EVMClient)(nil)
@@ -0,0 +1,54 @@
+package evm
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/reguluswee/walletus/common/bip"
+	"github.com/reguluswee/walletus/common/chain/dep"
+)
+
+func TestERC20TransferCalldata(t *testing.T) {
+	c := NewEVMClient(dep.ChainDef{Name: "BSC", CoinType: 60})
+	to := common.HexToAddress("0xe38533e11B680eAf4C9519Ea99B633BD3ef5c2F8")
+	data, err := c.erc20ABI.Pack("transfer", to, big.NewInt(1000000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := hex.EncodeToString(data)
+	want := "a9059cbb" +
+		"000000000000000000000000e38533e11b680eaf4c9519ea99b633bd3ef5c2f8" +
+		"00000000000000000000000000000000000000000000000000000000000f4240"
+	if got != want {
+		t.Fatalf("calldata mismatch:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestSigningKeyMatchesFrom(t *testing.T) {
+	enc, err := bip.GenerateMasterXprv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	kdf, _ := json.Marshal(enc.KDF)
+	key := dep.KeyRef{
+		EncMasterXprv: enc.EncMasterXprv,
+		EncMasterSeed: enc.EncMasterSeed,
+		KdfParams:     string(kdf),
+		Path:          "m/44'/60'/1'/0/2",
+	}
+	addr, _, err := bip.AddressAndPrivFromPath(enc, key.Path, "ETH")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := signingKey("BSC", common.HexToAddress(addr), key); err != nil {
+		t.Fatalf("expected key to match: %v", err)
+	}
+	other := common.HexToAddress("0xe38533e11B680eAf4C9519Ea99B633BD3ef5c2F8")
+	if _, err := signingKey("BSC", other, key); err != dep.ErrKeyMismatch {
+		t.Fatalf("expected ErrKeyMismatch, got %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+const subscribeTimeout = 10 * time.Second
+
+// errSubscriptionClosed 节点关闭了订阅但未给出原因
+var errSubscriptionClosed = errors.New("subscription closed")
+
+type headNotice struct {
+	Number hexutil.Uint64 `json:"number"`
+}
+
+type logNotice struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+}
+
+// SubscribeNewHeads 通过 WebSocket 订阅 newHeads，每个新区块向 heads 发送其高度
+// 阻塞直到连接断开、订阅出错或 ctx 取消，由调用方负责重连
+func SubscribeNewHeads(ctx context.Context, wsURL string, heads chan<- uint64) error {
+	return subscribe(ctx, wsURL, func(h headNotice) uint64 { return uint64(h.Number) }, heads, "newHeads")
+}
+
+// SubscribeTransferLogs 通过 WebSocket 订阅 tokens 的 Transfer 日志，每条日志向 blocks 发送其所在区块高度
+// 被重组移除的日志同样会推送，由扫描时的重组检测处理
+func SubscribeTransferLogs(ctx context.Context, wsURL string, tokens []string, blocks chan<- uint64) error {
+	addrs := make([]common.Address, 0, len(tokens))
+	for _, t := range tokens {
+		if !common.IsHexAddress(t) {
+			return fmt.Errorf("invalid token address: %s", t)
+		}
+		addrs = append(addrs, common.HexToAddress(t))
+	}
+	filter := map[string]any{
+		"address": addrs,
+		"topics":  []any{transferTopic},
+	}
+	return subscribe(ctx, wsURL, func(l logNotice) uint64 { return uint64(l.BlockNumber) }, blocks, "logs", filter)
+}
+
+// subscribe 建立 WebSocket 连接并执行 eth_subscribe，将每条通知转换为区块高度写入 out
+func subscribe[T any](ctx context.Context, wsURL string, height func(T) uint64, out chan<- uint64, args ...any) error {
+	rc, err := gethrpc.DialContext(ctx, wsURL)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	raw := make(chan T, 16)
+	ctx2, cancel := context.WithTimeout(ctx, subscribeTimeout)
+	sub, err := rc.EthSubscribe(ctx2, raw, args...)
+	cancel()
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-sub.Err():
+			if err == nil {
+				err = errSubscriptionClosed
+			}
+			return err
+		case v := <-raw:
+			select {
+			case out <- height(v):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package evm
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/reguluswee/walletus/common/chain/dep"
+)
+
+// BuildAndSignSweep 将 from 上的全部余额归集到 dest
+// 原生币按 21000 gas 与签名所用的费用上限预留手续费，剩余部分全部转出
+func (c *EVMClient) BuildAndSignSweep(ctx context.Context, network string, from string, dest string, opts any) ([]byte, string, string, error) {
+	o, err := dep.ToSweepOptions(opts)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if !common.IsHexAddress(from) || !common.IsHexAddress(dest) {
+		return nil, "", "", dep.ErrInvalidAddress
+	}
+	latest := dep.AnchorRef{Tag: "latest", Network: network}
+
+	if o.Token != "" {
+		if !common.IsHexAddress(o.Token) {
+			return nil, "", "", dep.ErrInvalidAddress
+		}
+		pair := [2]common.Address{common.HexToAddress(o.Token), common.HexToAddress(from)}
+		bals, err := c.batchedBalanceOf(ctx, network, [][2]common.Address{pair}, latest)
+		if err != nil {
+			return nil, "", "", err
+		}
+		bal := bals[pair]
+		if bal == nil || bal.Sign() <= 0 {
+			return nil, "", "", dep.ErrNothingToSweep
+		}
+		raw, hash, err := c.SignTransferToken(ctx, network, o.Token, from, dest, bal.String(), &o.SignOptions)
+		return raw, hash, bal.String(), err
+	}
+
+	nb, err := c.NativeBalance(ctx, network, from, latest)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	rc, err := c.pick(network)
+	if err != nil {
+		return nil, "", "", err
+	}
+	so := o.SignOptions
+	so.GasLimit = nativeTransferGas
+
+	var perGas *big.Int
+	baseFee, err := c.latestBaseFee(ctx, rc)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if baseFee != nil && !so.Legacy {
+		if so.MaxPriorityFeePerGas == nil {
+			so.MaxPriorityFeePerGas = c.suggestTip(ctx, rc)
+		}
+		if so.MaxFeePerGas == nil {
+			so.MaxFeePerGas = new(big.Int).Add(new(big.Int).Mul(baseFee, big.NewInt(2)), so.MaxPriorityFeePerGas)
+		}
+		perGas = so.MaxFeePerGas
+	} else {
+		if so.GasPrice == nil {
+			if so.GasPrice, err = c.gasPrice(ctx, rc); err != nil {
+				return nil, "", "", err
+			}
+		}
+		perGas = so.GasPrice
+	}
+
+	fee := new(big.Int).Mul(perGas, big.NewInt(nativeTransferGas))
+	amount := new(big.Int).Sub(nb.Amount, fee)
+	if amount.Sign() <= 0 {
+		return nil, "", "", dep.ErrNothingToSweep
+	}
+	raw, hash, err := c.SignTransferNative(ctx, network, from, dest, amount.String(), &so)
+	return raw, hash, amount.String(), err
+}
+
+var _ dep.Sweeper = (*EVMClient)(nil)
@@ -2,6 +2,7 @@ package chain
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/reguluswee/walletus/common/chain/dep"
@@ -23,9 +24,22 @@ type BalanceQuery struct {
 }
 
 type TransactionQuery struct {
+	Chain       dep.ChainDef
+	Network     string
+	TxHash      string
+	Consistency dep.Consistency
+}
+
+type BroadcastRequest struct {
 	Chain   dep.ChainDef
 	Network string
-	TxHash  string
+	RawTx   []byte
+}
+
+type FeeEstimateRequest struct {
+	Chain   dep.ChainDef
+	Network string
+	Query   dep.FeeQuery
 }
 
 func init() {
@@ -75,17 +89,40 @@ func (g *Gateway) GetBalances(ctx context.Context, q BalanceQuery) (*dep.BatchBa
 	return out, nil
 }
 
-func (g *Gateway) GetTransaction(ctx context.Context, q TransactionQuery) (any, error) {
+// GetTransaction 按 q.Consistency 取锚点后读取交易回执，确认数相对该锚点计算
+func (g *Gateway) GetTransaction(ctx context.Context, q TransactionQuery) (*dep.TxReceipt, error) {
 	client, ok := dep.GetClient(q.Chain)
 
 	if !ok {
 		return nil, dep.ErrUnsupportedChain
 	}
 
-	tx, err := client.GetTransaction(ctx, q.Chain.Name, q.TxHash)
+	anchor, err := client.Anchor(ctx, q.Chain.Name, q.Consistency)
 	if err != nil {
 		return nil, err
 	}
+	return client.GetTransaction(ctx, q.Chain.Name, q.TxHash, anchor)
+}
+
+// Broadcast 广播已签名交易，重复广播同一笔交易视为成功
+func (g *Gateway) Broadcast(ctx context.Context, q BroadcastRequest) (string, error) {
+	b, ok := dep.GetBroadcaster(q.Chain)
+	if !ok {
+		return "", dep.ErrUnsupportedChain
+	}
+
+	txHash, err := b.Broadcast(ctx, q.Chain.Name, q.RawTx)
+	if err != nil && !errors.Is(err, dep.ErrAlreadyKnown) {
+		return txHash, err
+	}
+	return txHash, nil
+}
 
-	return tx, nil
+// EstimateFee 估算一笔转账的手续费
+func (g *Gateway) EstimateFee(ctx context.Context, q FeeEstimateRequest) (*dep.FeeEstimate, error) {
+	e, ok := dep.GetFeeEstimator(q.Chain)
+	if !ok {
+		return nil, dep.ErrUnsupportedChain
+	}
+	return e.EstimateFee(ctx, q.Chain.Name, q.Query)
 }
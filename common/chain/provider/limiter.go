@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"sync"
+	"time"
+)
+
+// limiter 令牌桶，每秒补充 rate 个令牌，桶容量为一秒的配额
+// 一次请求的 cost 超过容量时（如大批量 JSON-RPC），桶满即可发出并透支，后续请求等待补足，平均速率仍不超过 rate
+type limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newLimiter rate 为 0 表示不限速，返回 nil
+func newLimiter(rate int) *limiter {
+	if rate <= 0 {
+		return nil
+	}
+	return &limiter{rate: float64(rate), burst: float64(rate), tokens: float64(rate), last: time.Now()}
+}
+
+// wait 距离可以发出 cost 个请求还需等待的时间，不扣除令牌
+func (l *limiter) wait(now time.Time, cost int) time.Duration {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.waitLocked(now, cost)
+}
+
+// take 令牌足够时扣除 cost 个并返回 true
+func (l *limiter) take(now time.Time, cost int) bool {
+	if l == nil {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.waitLocked(now, cost) > 0 {
+		return false
+	}
+	l.tokens -= float64(cost)
+	return true
+}
+
+func (l *limiter) waitLocked(now time.Time, cost int) time.Duration {
+	if now.After(l.last) {
+		l.tokens = min(l.tokens+now.Sub(l.last).Seconds()*l.rate, l.burst)
+		l.last = now
+	}
+	need := min(float64(cost), l.burst)
+	if l.tokens >= need {
+		return 0
+	}
+	return time.Duration((need - l.tokens) / l.rate * float64(time.Second))
+}
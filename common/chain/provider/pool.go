@@ -0,0 +1,329 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/reguluswee/walletus/common/config"
+	"github.com/reguluswee/walletus/common/log"
+)
+
+const (
+	// 连续失败该次数后剔除
+	ejectAfter = 3
+	// 首次剔除的冷却时间，恢复后再次失败则加倍，最长 maxCooldown
+	baseCooldown = 30 * time.Second
+	maxCooldown  = 5 * time.Minute
+	// 幂等读取最多尝试的端点数
+	maxAttempts = 3
+
+	// 错误率与延迟的指数移动平均系数
+	ewmaAlpha = 0.2
+	// 平均延迟超过该值的端点按比例降低权重
+	latencyRef = time.Second
+	// 错误率很高的端点仍保留少量流量，避免永远无法恢复
+	minHealthFactor = 0.05
+)
+
+var (
+	// ErrNoEndpoint 没有可用的端点
+	ErrNoEndpoint = errors.New("no rpc endpoint available")
+	// ErrRateLimited 全部端点的配额已用完，且在 ctx 截止前无法恢复
+	ErrRateLimited = errors.New("rpc rate limit exceeded")
+)
+
+// Endpoint 一个 RPC 提供商，Client 为各链自己的传输层客户端
+type Endpoint[T any] struct {
+	Name   string
+	URL    string
+	Weight int
+	Client T
+
+	limiter  *limiter // 按 Quote 限制每秒请求数，Quote 为 0 时为 nil
+	mu       sync.Mutex
+	latency  time.Duration // 成功请求延迟的移动平均
+	errRate  float64       // 失败率的移动平均
+	fails    int           // 连续失败次数
+	cooldown time.Duration // 最近一次剔除的冷却时间，成功后清零
+	until    time.Time     // 剔除截止时间
+}
+
+// Stats 端点健康状况，用于日志与排查
+type Stats struct {
+	Name     string
+	Weight   int
+	Latency  time.Duration
+	ErrRate  float64
+	Ejected  bool
+	Recovers time.Time
+}
+
+// Pool 同一条链的多个 RPC 提供商
+// RpcMapper.Quote 为提供商的每秒请求上限，同时作为选择权重（Quote 为 0 时不限速，权重为 1）；
+// 按权重随机选择有余量的端点，并按错误率与平均延迟下调权重；全部饱和时等待最早有余量的端点，而不是让提供商返回 429；
+// 连续失败的端点剔除一段冷却时间，幂等读取失败时换一个端点重试
+type Pool[T any] struct {
+	network   string
+	endpoints []*Endpoint[T]
+	isFailure func(error) bool
+}
+
+// New 为 rpcs 中的每个端点调用 dial 创建客户端，dial 失败的端点跳过
+// isFailure 判断错误是否由端点本身造成（网络错误、超时、限流、5xx 等），为 nil 时所有错误都视为端点故障；
+// 节点正常返回的业务错误（如 execution reverted）不影响端点健康，也不重试
+func New[T any](network string, rpcs []config.RpcMapper, dial func(url string) (T, error), isFailure func(error) bool) (*Pool[T], error) {
+	p := &Pool[T]{network: network, isFailure: isFailure}
+	for _, r := range rpcs {
+		client, err := dial(r.Rpc)
+		if err != nil {
+			log.Error("[rpc] ", network, " dial ", Alias(network, r.Rpc), " failed: ", err)
+			continue
+		}
+		p.endpoints = append(p.endpoints, &Endpoint[T]{
+			Name:    Alias(network, r.Rpc),
+			URL:     r.Rpc,
+			Weight:  max(r.Quote, 1),
+			Client:  client,
+			limiter: newLimiter(r.Quote),
+		})
+	}
+	if len(p.endpoints) == 0 {
+		return nil, fmt.Errorf("%w for %s", ErrNoEndpoint, network)
+	}
+	return p, nil
+}
+
+// Do 在选出的端点上执行 fn，并记录结果与延迟
+// cost 为本次消耗的配额，JSON-RPC 批量请求按元素个数计算；
+// retry 为 true 表示幂等读取：端点故障时换一个未试过的端点重试，最多 maxAttempts 个；
+// 此时若 ctx 带有截止时间，每次尝试最多使用剩余时间的一半，给后续端点留出时间
+func (p *Pool[T]) Do(ctx context.Context, cost int, retry bool, fn func(ctx context.Context, e *Endpoint[T]) error) error {
+	cost = max(cost, 1)
+	attempts := 1
+	if retry {
+		attempts = min(maxAttempts, len(p.endpoints))
+	}
+	tried := make(map[*Endpoint[T]]bool, attempts)
+	var lastErr error
+	for i := 0; i < attempts; i++ {
+		e, err := p.acquire(ctx, tried, cost)
+		if err != nil {
+			if lastErr != nil {
+				return lastErr
+			}
+			return err
+		}
+		if e == nil {
+			break
+		}
+		tried[e] = true
+
+		actx, cancel := ctx, context.CancelFunc(func() {})
+		if deadline, ok := ctx.Deadline(); ok && i < attempts-1 {
+			actx, cancel = context.WithTimeout(ctx, time.Until(deadline)/2)
+		}
+		start := time.Now()
+		err = fn(actx, e)
+		cancel()
+
+		if err == nil {
+			e.success(time.Since(start))
+			if name, ok := ctx.Value(servedKey{}).(*string); ok {
+				*name = e.Name
+			}
+			return nil
+		}
+		// 调用方已放弃，不计入端点健康
+		if ctx.Err() != nil {
+			return err
+		}
+		if p.isFailure != nil && !p.isFailure(err) && actx.Err() == nil {
+			e.success(time.Since(start))
+			return err
+		}
+		e.failure(p.network)
+		lastErr = err
+	}
+	if lastErr == nil {
+		return fmt.Errorf("%w for %s", ErrNoEndpoint, p.network)
+	}
+	return lastErr
+}
+
+// Stats 全部端点的健康状况
+func (p *Pool[T]) Stats() []Stats {
+	now := time.Now()
+	out := make([]Stats, 0, len(p.endpoints))
+	for _, e := range p.endpoints {
+		e.mu.Lock()
+		out = append(out, Stats{
+			Name:     e.Name,
+			Weight:   e.Weight,
+			Latency:  e.latency,
+			ErrRate:  e.errRate,
+			Ejected:  now.Before(e.until),
+			Recovers: e.until,
+		})
+		e.mu.Unlock()
+	}
+	return out
+}
+
+// acquire 选出端点并扣除 cost 个令牌；候选端点都已饱和时等待最早有余量的一个
+// 等待时间超过 ctx 的剩余时间时直接返回 ErrRateLimited；没有候选端点时返回 nil
+func (p *Pool[T]) acquire(ctx context.Context, tried map[*Endpoint[T]]bool, cost int) (*Endpoint[T], error) {
+	for {
+		now := time.Now()
+		e, wait := p.pick(tried, cost, now)
+		if e == nil {
+			return nil, nil
+		}
+		if wait == 0 {
+			if e.limiter.take(now, cost) {
+				return e, nil
+			}
+			// 令牌被并发请求取走，重新选择
+			continue
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return nil, fmt.Errorf("%w for %s", ErrRateLimited, p.network)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// pick 在未被剔除、未试过且有余量的端点中加权随机选择，wait 为 0；
+// 都已饱和时返回最早有余量的端点及需要等待的时间；全部被剔除时选最早恢复的一个
+func (p *Pool[T]) pick(tried map[*Endpoint[T]]bool, cost int, now time.Time) (*Endpoint[T], time.Duration) {
+	var total float64
+	weights := make([]float64, len(p.endpoints))
+	var fallback, soonest *Endpoint[T]
+	var fallbackUntil time.Time
+	var soonestWait time.Duration
+	for i, e := range p.endpoints {
+		if tried[e] {
+			continue
+		}
+		w, until := e.effectiveWeight(now)
+		if w == 0 {
+			if fallback == nil || until.Before(fallbackUntil) {
+				fallback, fallbackUntil = e, until
+			}
+			continue
+		}
+		if wait := e.limiter.wait(now, cost); wait > 0 {
+			if soonest == nil || wait < soonestWait {
+				soonest, soonestWait = e, wait
+			}
+			continue
+		}
+		weights[i] = w
+		total += w
+	}
+	if total == 0 {
+		if soonest != nil {
+			return soonest, soonestWait
+		}
+		if fallback != nil {
+			return fallback, fallback.limiter.wait(now, cost)
+		}
+		return nil, 0
+	}
+	r := rand.Float64() * total
+	for i, w := range weights {
+		if w == 0 {
+			continue
+		}
+		if r < w {
+			return p.endpoints[i], 0
+		}
+		r -= w
+	}
+	// 浮点误差兜底
+	for i := len(weights) - 1; i >= 0; i-- {
+		if weights[i] > 0 {
+			return p.endpoints[i], 0
+		}
+	}
+	return nil, 0
+}
+
+// effectiveWeight 被剔除时返回 0 及恢复时间
+func (e *Endpoint[T]) effectiveWeight(now time.Time) (float64, time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if now.Before(e.until) {
+		return 0, e.until
+	}
+	w := float64(e.Weight) * max(1-e.errRate, minHealthFactor)
+	if e.latency > latencyRef {
+		w *= float64(latencyRef) / float64(e.latency)
+	}
+	return w, e.until
+}
+
+func (e *Endpoint[T]) success(d time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.fails = 0
+	e.cooldown = 0
+	e.errRate *= 1 - ewmaAlpha
+	if e.latency == 0 {
+		e.latency = d
+	} else {
+		e.latency = time.Duration(float64(e.latency)*(1-ewmaAlpha) + float64(d)*ewmaAlpha)
+	}
+}
+
+// failure 连续失败达到阈值时剔除；冷却结束后的第一次失败即再次剔除，冷却时间加倍
+func (e *Endpoint[T]) failure(network string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.fails++
+	e.errRate = e.errRate*(1-ewmaAlpha) + ewmaAlpha
+	if e.fails < ejectAfter && e.cooldown == 0 {
+		return
+	}
+	if e.cooldown == 0 {
+		e.cooldown = baseCooldown
+	} else {
+		e.cooldown = min(e.cooldown*2, maxCooldown)
+	}
+	e.until = time.Now().Add(e.cooldown)
+	e.fails = 0
+	log.Info("[rpc] ", network, " eject ", e.Name, " for ", e.cooldown)
+}
+
+// Alias 端点的简短名称，用于日志与 AnchorRef.Provider，不包含路径中可能携带的 API Key
+func Alias(chain, url string) string {
+	u := url
+	if i := strings.Index(u, "://"); i >= 0 {
+		u = u[i+3:]
+	}
+	if j := strings.Index(u, "/"); j >= 0 {
+		u = u[:j]
+	}
+	if len(u) > 28 {
+		u = u[:28]
+	}
+	return strings.ToLower(chain) + ":" + u
+}
+
+type servedKey struct{}
+
+// WithServed 返回的 ctx 传给 Do 后，成功时 name 记录实际提供服务的端点，用于填写 AnchorRef.Provider
+func WithServed(ctx context.Context) (context.Context, *string) {
+	name := new(string)
+	return context.WithValue(ctx, servedKey{}, name), name
+}
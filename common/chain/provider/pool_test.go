@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/reguluswee/walletus/common/config"
+)
+
+var errApp = errors.New("execution reverted")
+
+func newTestPool(t *testing.T, quotes ...int) *Pool[int] {
+	t.Helper()
+	var rpcs []config.RpcMapper
+	for i, q := range quotes {
+		rpcs = append(rpcs, config.RpcMapper{Rpc: "https://node" + string(rune('a'+i)) + ".example", Quote: q})
+	}
+	i := 0
+	p, err := New("ETH", rpcs, func(string) (int, error) { i++; return i - 1, nil }, func(err error) bool { return !errors.Is(err, errApp) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestDoRetriesOnEndpointFailure(t *testing.T) {
+	p := newTestPool(t, 1, 1, 1)
+	calls := 0
+	err := p.Do(context.Background(), 1, true, func(ctx context.Context, e *Endpoint[int]) error {
+		calls++
+		if calls < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("err=%v calls=%d", err, calls)
+	}
+}
+
+func TestDoNoRetryForAppErrorOrWrite(t *testing.T) {
+	p := newTestPool(t, 1, 1)
+	calls := 0
+	err := p.Do(context.Background(), 1, true, func(ctx context.Context, e *Endpoint[int]) error {
+		calls++
+		return errApp
+	})
+	if !errors.Is(err, errApp) || calls != 1 {
+		t.Fatalf("app error: err=%v calls=%d", err, calls)
+	}
+
+	calls = 0
+	_ = p.Do(context.Background(), 1, false, func(ctx context.Context, e *Endpoint[int]) error {
+		calls++
+		return errors.New("timeout")
+	})
+	if calls != 1 {
+		t.Fatalf("write retried %d times", calls)
+	}
+}
+
+func TestEjectAfterConsecutiveFailures(t *testing.T) {
+	p := newTestPool(t, 1, 1)
+	bad := p.endpoints[0]
+	for i := 0; i < ejectAfter; i++ {
+		bad.failure("ETH")
+	}
+	for i := 0; i < 50; i++ {
+		if e := pickNow(p); e == bad {
+			t.Fatal("picked ejected endpoint")
+		}
+	}
+	// 全部剔除时仍返回最早恢复的端点
+	p.endpoints[1].failure("ETH")
+	p.endpoints[1].failure("ETH")
+	p.endpoints[1].failure("ETH")
+	if e := pickNow(p); e == nil {
+		t.Fatal("no fallback endpoint")
+	}
+}
+
+func TestServedName(t *testing.T) {
+	p := newTestPool(t, 1)
+	ctx, served := WithServed(context.Background())
+	if err := p.Do(ctx, 1, true, func(ctx context.Context, e *Endpoint[int]) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if *served != "eth:nodea.example" {
+		t.Fatalf("served %q", *served)
+	}
+}
+
+func pickNow[T any](p *Pool[T]) *Endpoint[T] {
+	e, _ := p.pick(nil, 1, time.Now())
+	return e
+}
+
+func TestLimiterBatchCost(t *testing.T) {
+	now := time.Now()
+	l := newLimiter(10)
+	// 超过桶容量的批量请求在桶满时可以发出，随后透支
+	if !l.take(now, 25) {
+		t.Fatal("full bucket should allow oversized batch")
+	}
+	if w := l.wait(now, 1); w < time.Second {
+		t.Fatalf("wait %v after overdraft", w)
+	}
+	if newLimiter(0) != nil || newLimiter(0).wait(now, 100) != 0 {
+		t.Fatal("zero quote should not limit")
+	}
+}
+
+func TestSaturatedEndpointRoutedAway(t *testing.T) {
+	p := newTestPool(t, 2, 1000)
+	busy := p.endpoints[0]
+	busy.limiter.take(time.Now(), 2)
+	for i := 0; i < 50; i++ {
+		if e := pickNow(p); e == busy {
+			t.Fatal("picked saturated endpoint")
+		}
+	}
+}
+
+func TestSaturatedPoolWaitsOrFails(t *testing.T) {
+	p := newTestPool(t, 10)
+	p.endpoints[0].limiter.take(time.Now(), 10)
+
+	// 约 100ms 后恢复一个令牌，截止时间足够时等待
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := p.Do(ctx, 1, true, func(ctx context.Context, e *Endpoint[int]) error { return nil }); err != nil {
+		t.Fatalf("expected wait then success: %v", err)
+	}
+
+	p.endpoints[0].limiter.take(time.Now(), 10)
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel2()
+	err := p.Do(ctx2, 5, true, func(ctx context.Context, e *Endpoint[int]) error { return nil })
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+}
@@ -0,0 +1,300 @@
+package solana
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+const blockTimeout = 20 * time.Second
+
+// 节点对 getBlock 的错误码
+const (
+	errCodeBlockNotAvailable   = -32004 // 区块尚未可用，稍后重试
+	errCodeSlotSkipped         = -32007 // slot 被跳过或因快照缺失
+	errCodeLongTermStorageSkip = -32009 // slot 被跳过或不在长期存储中
+)
+
+// ErrSlotSkipped 该 slot 没有出块，扫描时视为空块
+var ErrSlotSkipped = errors.New("slot skipped")
+
+// Block 扫描用的区块，Transfers 为执行成功的交易中解析出的 SOL 与 SPL 转账
+type Block struct {
+	Slot       uint64
+	ParentSlot uint64
+	Hash       string
+	ParentHash string
+	Height     uint64
+	Time       int64
+	Transfers  []Transfer
+}
+
+// Transfer 一笔 SOL 或 SPL 转账，Token 为空表示 SOL
+// SPL 转账的 To / From 为 Token 账户的所有者钱包地址，Account 为收款 Token 账户
+// Index 为该指令在交易中按外层、内层顺序展开后的序号，同一交易内唯一
+type Transfer struct {
+	Signature string
+	Index     int
+	Token     string
+	From      string
+	To        string
+	Account   string
+	Amount    *big.Int
+}
+
+type rpcInstruction struct {
+	Program string          `json:"program"`
+	Parsed  json.RawMessage `json:"parsed"`
+}
+
+type rpcTokenBalance struct {
+	AccountIndex  int    `json:"accountIndex"`
+	Mint          string `json:"mint"`
+	Owner         string `json:"owner"`
+	UiTokenAmount struct {
+		Amount string `json:"amount"`
+	} `json:"uiTokenAmount"`
+}
+
+type rpcAccountKey struct {
+	Pubkey string `json:"pubkey"`
+}
+
+type rpcBlockTx struct {
+	Transaction struct {
+		Signatures []string `json:"signatures"`
+		Message    struct {
+			AccountKeys  []rpcAccountKey  `json:"accountKeys"`
+			Instructions []rpcInstruction `json:"instructions"`
+		} `json:"message"`
+	} `json:"transaction"`
+	Meta *struct {
+		Err               json.RawMessage   `json:"err"`
+		Fee               uint64            `json:"fee"`
+		PreTokenBalances  []rpcTokenBalance `json:"preTokenBalances"`
+		PostTokenBalances []rpcTokenBalance `json:"postTokenBalances"`
+		InnerInstructions []struct {
+			Index        int              `json:"index"`
+			Instructions []rpcInstruction `json:"instructions"`
+		} `json:"innerInstructions"`
+	} `json:"meta"`
+}
+
+type rpcBlock struct {
+	Blockhash         string       `json:"blockhash"`
+	PreviousBlockhash string       `json:"previousBlockhash"`
+	ParentSlot        uint64       `json:"parentSlot"`
+	BlockHeight       *uint64      `json:"blockHeight"`
+	BlockTime         *int64       `json:"blockTime"`
+	Transactions      []rpcBlockTx `json:"transactions"`
+}
+
+// Slot 当前 slot，commitment 为空时使用 confirmed
+func (c *SOLClient) Slot(ctx context.Context, network, commitment string) (uint64, error) {
+	cli, err := c.pick(network)
+	if err != nil {
+		return 0, err
+	}
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+
+	result, err := cli.callRPC(ctx2, "getSlot", []interface{}{
+		map[string]interface{}{"commitment": getCommitmentFromTag(commitment)},
+	})
+	if err != nil {
+		return 0, err
+	}
+	var slot uint64
+	if err := json.Unmarshal(result, &slot); err != nil {
+		return 0, fmt.Errorf("unmarshal slot: %w", err)
+	}
+	return slot, nil
+}
+
+// BlockBySlot 以 jsonParsed 读取完整区块，slot 被跳过时返回 ErrSlotSkipped
+func (c *SOLClient) BlockBySlot(ctx context.Context, network string, slot uint64, commitment string) (*Block, error) {
+	cli, err := c.pick(network)
+	if err != nil {
+		return nil, err
+	}
+	ctx2, cancel := context.WithTimeout(ctx, blockTimeout)
+	defer cancel()
+
+	// getBlock 不支持 processed
+	if commitment != "finalized" {
+		commitment = "confirmed"
+	}
+	result, err := cli.callRPC(ctx2, "getBlock", []interface{}{
+		slot,
+		map[string]interface{}{
+			"encoding":                       "jsonParsed",
+			"transactionDetails":             "full",
+			"rewards":                        false,
+			"maxSupportedTransactionVersion": 0,
+			"commitment":                     commitment,
+		},
+	})
+	if err != nil {
+		var re *RPCError
+		if errors.As(err, &re) && (re.Code == errCodeSlotSkipped || re.Code == errCodeLongTermStorageSkip) {
+			return nil, ErrSlotSkipped
+		}
+		return nil, err
+	}
+	if string(result) == "null" {
+		return nil, ErrSlotSkipped
+	}
+
+	var raw rpcBlock
+	if err := json.Unmarshal(result, &raw); err != nil {
+		return nil, fmt.Errorf("unmarshal block: %w", err)
+	}
+	return decodeBlock(slot, &raw), nil
+}
+
+// IsBlockNotAvailable 区块尚未在该节点可用，应稍后重试
+func IsBlockNotAvailable(err error) bool {
+	var re *RPCError
+	return errors.As(err, &re) && re.Code == errCodeBlockNotAvailable
+}
+
+func decodeBlock(slot uint64, raw *rpcBlock) *Block {
+	b := &Block{
+		Slot:       slot,
+		ParentSlot: raw.ParentSlot,
+		Hash:       raw.Blockhash,
+		ParentHash: raw.PreviousBlockhash,
+	}
+	if raw.BlockHeight != nil {
+		b.Height = *raw.BlockHeight
+	}
+	if raw.BlockTime != nil {
+		b.Time = *raw.BlockTime
+	}
+	for i := range raw.Transactions {
+		b.Transfers = append(b.Transfers, decodeTransfers(&raw.Transactions[i])...)
+	}
+	return b
+}
+
+// tokenAccount Token 账户的 mint 与所有者，取自交易前后的 Token 余额
+type tokenAccount struct {
+	Mint  string
+	Owner string
+}
+
+// decodeTransfers 按外层指令及其内层指令的顺序解析转账，失败的交易不产生转账
+func decodeTransfers(tx *rpcBlockTx) []Transfer {
+	meta := tx.Meta
+	if meta == nil || (len(meta.Err) > 0 && string(meta.Err) != "null") || len(tx.Transaction.Signatures) == 0 {
+		return nil
+	}
+	sig := tx.Transaction.Signatures[0]
+
+	keys := tx.Transaction.Message.AccountKeys
+	accounts := make(map[string]tokenAccount)
+	for _, balances := range [][]rpcTokenBalance{meta.PreTokenBalances, meta.PostTokenBalances} {
+		for _, tb := range balances {
+			if tb.AccountIndex >= 0 && tb.AccountIndex < len(keys) {
+				accounts[keys[tb.AccountIndex].Pubkey] = tokenAccount{Mint: tb.Mint, Owner: tb.Owner}
+			}
+		}
+	}
+
+	inner := make(map[int][]rpcInstruction, len(meta.InnerInstructions))
+	for _, in := range meta.InnerInstructions {
+		inner[in.Index] = in.Instructions
+	}
+
+	var out []Transfer
+	idx := 0
+	visit := func(ix rpcInstruction) {
+		if t, ok := decodeInstruction(ix, accounts); ok {
+			t.Signature = sig
+			t.Index = idx
+			out = append(out, t)
+		}
+		idx++
+	}
+	for i, ix := range tx.Transaction.Message.Instructions {
+		visit(ix)
+		for _, in := range inner[i] {
+			visit(in)
+		}
+	}
+	return out
+}
+
+func decodeInstruction(ix rpcInstruction, accounts map[string]tokenAccount) (Transfer, bool) {
+	// 部分程序（如 memo）的 parsed 为字符串
+	var parsed struct {
+		Type string `json:"type"`
+		Info struct {
+			Source            string `json:"source"`
+			Destination       string `json:"destination"`
+			Lamports          uint64 `json:"lamports"`
+			Amount            string `json:"amount"`
+			Mint              string `json:"mint"`
+			Authority         string `json:"authority"`
+			MultisigAuthority string `json:"multisigAuthority"`
+			TokenAmount       struct {
+				Amount string `json:"amount"`
+			} `json:"tokenAmount"`
+		} `json:"info"`
+	}
+	if len(ix.Parsed) == 0 || ix.Parsed[0] != '{' || json.Unmarshal(ix.Parsed, &parsed) != nil {
+		return Transfer{}, false
+	}
+	info := parsed.Info
+
+	switch ix.Program {
+	case "system":
+		if (parsed.Type != "transfer" && parsed.Type != "transferWithSeed") || info.Lamports == 0 {
+			return Transfer{}, false
+		}
+		return Transfer{
+			From:    info.Source,
+			To:      info.Destination,
+			Account: info.Destination,
+			Amount:  new(big.Int).SetUint64(info.Lamports),
+		}, true
+
+	case "spl-token", "spl-token-2022":
+		amount := info.Amount
+		if parsed.Type == "transferChecked" {
+			amount = info.TokenAmount.Amount
+		} else if parsed.Type != "transfer" {
+			return Transfer{}, false
+		}
+		value, ok := new(big.Int).SetString(amount, 10)
+		if !ok || value.Sign() <= 0 {
+			return Transfer{}, false
+		}
+		dest, ok := accounts[info.Destination]
+		if !ok {
+			return Transfer{}, false
+		}
+		mint := info.Mint
+		if mint == "" {
+			mint = dest.Mint
+		}
+		from := info.Authority
+		if from == "" {
+			from = info.MultisigAuthority
+		}
+		if src, ok := accounts[info.Source]; ok && src.Owner != "" {
+			from = src.Owner
+		}
+		return Transfer{
+			Token:   mint,
+			From:    from,
+			To:      dest.Owner,
+			Account: info.Destination,
+			Amount:  value,
+		}, true
+	}
+	return Transfer{}, false
+}
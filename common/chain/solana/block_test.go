@@ -0,0 +1,67 @@
+package solana
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const blockTxFixture = `{
+  "transaction": {
+    "signatures": ["sig1"],
+    "message": {
+      "accountKeys": [
+        {"pubkey": "Payer"}, {"pubkey": "SrcATA"}, {"pubkey": "DstATA"}, {"pubkey": "Wallet"}
+      ],
+      "instructions": [
+        {"program": "system", "parsed": {"type": "transfer", "info": {"source": "Payer", "destination": "Wallet", "lamports": 5000}}},
+        {"program": "spl-memo", "parsed": "hello"},
+        {"program": "spl-token", "parsed": {"type": "transferChecked", "info": {"source": "SrcATA", "destination": "DstATA", "mint": "Mint", "authority": "Payer", "tokenAmount": {"amount": "2500000"}}}}
+      ]
+    }
+  },
+  "meta": {
+    "err": null,
+    "preTokenBalances": [
+      {"accountIndex": 1, "mint": "Mint", "owner": "Payer", "uiTokenAmount": {"amount": "9000000"}}
+    ],
+    "postTokenBalances": [
+      {"accountIndex": 1, "mint": "Mint", "owner": "Payer", "uiTokenAmount": {"amount": "6000000"}},
+      {"accountIndex": 2, "mint": "Mint", "owner": "Wallet", "uiTokenAmount": {"amount": "3000000"}}
+    ],
+    "innerInstructions": [
+      {"index": 0, "instructions": [
+        {"program": "spl-token", "parsed": {"type": "transfer", "info": {"source": "SrcATA", "destination": "DstATA", "authority": "Payer", "amount": "500000"}}}
+      ]}
+    ]
+  }
+}`
+
+func TestDecodeTransfers(t *testing.T) {
+	var tx rpcBlockTx
+	if err := json.Unmarshal([]byte(blockTxFixture), &tx); err != nil {
+		t.Fatal(err)
+	}
+	got := decodeTransfers(&tx)
+	if len(got) != 3 {
+		t.Fatalf("got %d transfers, want 3: %+v", len(got), got)
+	}
+
+	sol := got[0]
+	if sol.Token != "" || sol.To != "Wallet" || sol.Amount.Uint64() != 5000 || sol.Index != 0 || sol.Signature != "sig1" {
+		t.Fatalf("unexpected sol transfer %+v", sol)
+	}
+	// 内层指令紧跟所属的外层指令
+	inner := got[1]
+	if inner.Token != "Mint" || inner.To != "Wallet" || inner.Account != "DstATA" || inner.Amount.String() != "500000" || inner.Index != 1 {
+		t.Fatalf("unexpected inner transfer %+v", inner)
+	}
+	checked := got[2]
+	if checked.Token != "Mint" || checked.From != "Payer" || checked.To != "Wallet" || checked.Amount.String() != "2500000" || checked.Index != 3 {
+		t.Fatalf("unexpected checked transfer %+v", checked)
+	}
+
+	tx.Meta.Err = json.RawMessage(`{"InstructionError":[0,"Custom"]}`)
+	if got := decodeTransfers(&tx); len(got) != 0 {
+		t.Fatalf("failed transaction should yield no transfers, got %d", len(got))
+	}
+}
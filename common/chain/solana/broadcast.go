@@ -0,0 +1,47 @@
+package solana
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mr-tron/base58"
+	"github.com/reguluswee/walletus/common/chain/dep"
+)
+
+// Broadcast 通过 sendTransaction 广播已签名交易，交易哈希为第一个签名
+func (c *SOLClient) Broadcast(ctx context.Context, network string, rawTx []byte) (string, error) {
+	// 签名数量使用 compact-u16 编码，单签名交易首字节为 1
+	if len(rawTx) < 65 || rawTx[0] == 0 || rawTx[0] >= 0x80 {
+		return "", fmt.Errorf("raw tx is not a signed transaction")
+	}
+	signature := base58.Encode(rawTx[1:65])
+
+	cli, err := c.pick(network)
+	if err != nil {
+		return "", err
+	}
+
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+
+	params := []interface{}{
+		base64.StdEncoding.EncodeToString(rawTx),
+		map[string]interface{}{
+			"encoding":            "base64",
+			"preflightCommitment": "confirmed",
+		},
+	}
+	result, err := cli.callRPC(ctx2, "sendTransaction", params)
+	if err != nil {
+		return signature, dep.ClassifyBroadcastError(err.Error())
+	}
+	var sent string
+	if err := json.Unmarshal(result, &sent); err == nil && sent != "" && sent != signature {
+		return sent, fmt.Errorf("node returned unexpected signature %s", sent)
+	}
+	return signature, nil
+}
+
+var _ dep.Broadcaster = (*SOLClient)(nil)
@@ -15,17 +15,36 @@ import (
 	"time"
 
 	"github.com/reguluswee/walletus/common/chain/dep"
+	"github.com/reguluswee/walletus/common/chain/provider"
 	"github.com/reguluswee/walletus/common/config"
 	"golang.org/x/crypto/sha3"
 	"golang.org/x/sync/singleflight"
 )
 
-type rpcClient struct {
+// endpoint 一个 Solana JSON-RPC 提供商
+type endpoint struct {
 	baseURL string
 	client  *http.Client
-	name    string
 }
 
+// rpcClient 一条链上全部提供商的 JSON-RPC 客户端，每次请求经 provider.Pool 选择端点
+type rpcClient struct {
+	pool *provider.Pool[*endpoint]
+}
+
+// statusError 非 200 的 HTTP 响应
+type statusError struct {
+	Code int
+	Body string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("http status %d: %s", e.Code, e.Body)
+}
+
+// 节点不健康（落后过多等），换一个端点重试
+const errCodeNodeUnhealthy = -32005
+
 type SOLClient struct {
 	mu         sync.RWMutex
 	clients    map[string]*rpcClient // network -> client
@@ -75,29 +94,41 @@ func (c *SOLClient) ensureClient(network string) (*rpcClient, error) {
 		return nil, fmt.Errorf("missing RPC config for %s", network)
 	}
 
-	// 取第一个可用的 RPC 端点
-	var rpcCli *rpcClient
-	for _, url := range cc.GetRpc() {
-		baseURL := strings.TrimSuffix(url, "/")
-		rpcCli = &rpcClient{
-			baseURL: baseURL,
-			client: &http.Client{
-				Timeout: c.reqTimeout,
-			},
-			name: shortAlias(network, url),
-		}
-		break
+	pool, err := provider.New(network, cc.GetRpcMapper(), func(url string) (*endpoint, error) {
+		return &endpoint{
+			baseURL: strings.TrimSuffix(url, "/"),
+			client:  &http.Client{Timeout: c.reqTimeout},
+		}, nil
+	}, isEndpointFailure)
+	if err != nil {
+		return nil, err
 	}
 
-	if rpcCli == nil {
-		return nil, fmt.Errorf("no RPC available for %s", network)
+	client = &rpcClient{pool: pool}
+	c.clients[network] = client
+	return client, nil
+}
+
+// isEndpointFailure 节点正常返回的 JSON-RPC 错误不归咎于端点，节点不健康除外
+func isEndpointFailure(err error) bool {
+	var re *RPCError
+	if errors.As(err, &re) {
+		return re.Code == errCodeNodeUnhealthy
 	}
+	return true
+}
+
+// RPCError 节点返回的 JSON-RPC 错误，调用方可按 Code 区分跳过的 slot 等情况
+type RPCError struct {
+	Code    int
+	Message string
+}
 
-	c.clients[network] = rpcCli
-	return rpcCli, nil
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
 }
 
-// callRPC 调用 Solana JSON-RPC API
+// callRPC 调用 Solana JSON-RPC API，除 sendTransaction 外在端点故障时换一个端点重试
 func (cli *rpcClient) callRPC(ctx context.Context, method string, params []interface{}) (json.RawMessage, error) {
 	reqBody := map[string]interface{}{
 		"jsonrpc": "2.0",
@@ -111,14 +142,23 @@ func (cli *rpcClient) callRPC(ctx context.Context, method string, params []inter
 		return nil, fmt.Errorf("marshal request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", cli.baseURL, bytes.NewBuffer(bodyBytes))
+	var result json.RawMessage
+	err = cli.pool.Do(ctx, 1, method != "sendTransaction", func(ctx context.Context, e *provider.Endpoint[*endpoint]) error {
+		result, err = e.Client.call(ctx, bodyBytes)
+		return err
+	})
+	return result, err
+}
+
+func (e *endpoint) call(ctx context.Context, bodyBytes []byte) (json.RawMessage, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", e.baseURL, bytes.NewBuffer(bodyBytes))
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := cli.client.Do(req)
+	resp, err := e.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("http request: %w", err)
 	}
@@ -130,7 +170,7 @@ func (cli *rpcClient) callRPC(ctx context.Context, method string, params []inter
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("http status %d: %s", resp.StatusCode, string(respBody))
+		return nil, &statusError{Code: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var result struct {
@@ -148,7 +188,7 @@ func (cli *rpcClient) callRPC(ctx context.Context, method string, params []inter
 	}
 
 	if result.Error != nil {
-		return nil, fmt.Errorf("rpc error %d: %s", result.Error.Code, result.Error.Message)
+		return nil, &RPCError{Code: result.Error.Code, Message: result.Error.Message}
 	}
 
 	return result.Result, nil
@@ -162,6 +202,7 @@ func (c *SOLClient) Anchor(ctx context.Context, network string, cs dep.Consisten
 
 	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
 	defer cancel()
+	ctx2, served := provider.WithServed(ctx2)
 
 	tag := cs.Mode
 	if tag == "" {
@@ -198,7 +239,7 @@ func (c *SOLClient) Anchor(ctx context.Context, network string, cs dep.Consisten
 		Height:   slot,
 		Tag:      cs.Mode,
 		Network:  network,
-		Provider: cli.name,
+		Provider: *served,
 	}, nil
 }
 
@@ -457,31 +498,12 @@ func (c *SOLClient) getSPLTokenBalance(ctx context.Context, cli *rpcClient, owne
 	}, nil
 }
 
-func (c *SOLClient) GetTransaction(ctx context.Context, network string, txHash string) (any, error) {
-	return nil, errors.New("not implemented")
-}
-
 func MustRegister() {
 	dep.Register(dep.GetSupportedSol(), NewSOLClient())
 }
 
 // ---------------- 工具函数 ----------------
 
-// shortAlias 生成 RPC 提供商的简短别名
-func shortAlias(chain, url string) string {
-	u := url
-	if i := strings.Index(u, "://"); i >= 0 {
-		u = u[i+3:]
-	}
-	if j := strings.Index(u, "/"); j >= 0 {
-		u = u[:j]
-	}
-	if len(u) > 28 {
-		u = u[:28]
-	}
-	return strings.ToLower(chain) + ":" + u
-}
-
 // chunkStrings 将字符串切片分块
 func chunkStrings(ss []string, n int) [][]string {
 	if n <= 0 || len(ss) <= n {
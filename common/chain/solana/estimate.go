@@ -0,0 +1,98 @@
+package solana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/reguluswee/walletus/common/chain/dep"
+)
+
+// 未设置 ComputeBudget 时每条指令默认的计算单元上限，优先费按上限计费
+const defaultComputeUnitsPerIx = 200_000
+
+var (
+	feeTierLevels      = []string{"slow", "standard", "fast"}
+	feeTierPercentiles = []int{25, 50, 90}
+)
+
+// EstimateFee 基础费为单签名费用，优先费三档取自 getRecentPrioritizationFees 的分位数
+func (c *SOLClient) EstimateFee(ctx context.Context, network string, q dep.FeeQuery) (*dep.FeeEstimate, error) {
+	cli, err := c.pick(network)
+	if err != nil {
+		return nil, err
+	}
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+
+	// 传入相关账户可以得到写锁竞争更准确的优先费
+	var accounts []string
+	for _, a := range []string{q.From, q.Token} {
+		if a == "" {
+			continue
+		}
+		if _, err := parsePubkey(a); err != nil {
+			return nil, dep.ErrInvalidAddress
+		}
+		accounts = append(accounts, a)
+	}
+	params := []interface{}{}
+	if len(accounts) > 0 {
+		params = append(params, accounts)
+	}
+	result, err := cli.callRPC(ctx2, "getRecentPrioritizationFees", params)
+	if err != nil {
+		return nil, err
+	}
+	var fees []struct {
+		Slot              uint64 `json:"slot"`
+		PrioritizationFee uint64 `json:"prioritizationFee"`
+	}
+	if err := json.Unmarshal(result, &fees); err != nil {
+		return nil, fmt.Errorf("unmarshal prioritization fees: %w", err)
+	}
+	samples := make([]uint64, 0, len(fees))
+	for _, f := range fees {
+		samples = append(samples, f.PrioritizationFee)
+	}
+
+	// 原生币转账一条指令，代币转账按 TransferChecked + 创建关联账户计
+	numIx := uint64(1)
+	if q.Token != "" {
+		numIx = 2
+	}
+	units := numIx * defaultComputeUnitsPerIx
+
+	base := big.NewInt(lamportsPerSignature)
+	out := &dep.FeeEstimate{Symbol: "SOL", Decimals: 9, BaseFee: base, GasLimit: units}
+	for i, level := range feeTierLevels {
+		price := percentile(samples, feeTierPercentiles[i])
+		// micro-lamports/CU * CU / 1e6，向上取整
+		prio := new(big.Int).Mul(new(big.Int).SetUint64(price), new(big.Int).SetUint64(units))
+		prio.Add(prio, big.NewInt(999_999)).Div(prio, big.NewInt(1_000_000))
+		out.Tiers = append(out.Tiers, dep.FeeTier{
+			Level:       level,
+			PriorityFee: new(big.Int).SetUint64(price),
+			Fee:         prio.Add(prio, base),
+		})
+	}
+	return out, nil
+}
+
+// percentile 返回样本的 p 分位数（最近秩法），无样本时为 0
+func percentile(samples []uint64, p int) uint64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	sorted := append([]uint64(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	idx := (p*len(sorted) + 99) / 100
+	if idx < 1 {
+		idx = 1
+	}
+	return sorted[idx-1]
+}
+
+var _ dep.FeeEstimator = (*SOLClient)(nil)
@@ -0,0 +1,16 @@
+package solana
+
+import "testing"
+
+func TestPercentile(t *testing.T) {
+	samples := []uint64{50, 0, 10, 40, 20, 30, 0, 0, 100, 60}
+	cases := map[int]uint64{25: 0, 50: 20, 90: 60, 100: 100}
+	for p, want := range cases {
+		if got := percentile(samples, p); got != want {
+			t.Fatalf("p%d: expected %d, got %d", p, want, got)
+		}
+	}
+	if got := percentile(nil, 50); got != 0 {
+		t.Fatalf("empty samples: %d", got)
+	}
+}
@@ -0,0 +1,84 @@
+package solana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/reguluswee/walletus/common/chain/dep"
+)
+
+const (
+	// SPL Token 账户大小；Token-2022 的关联账户带 ImmutableOwner 扩展
+	tokenAccountSize     = 165
+	token2022AccountSize = 170
+)
+
+// QuoteTokenTransferGas 计算 SPL 转账前 from 需要持有的 lamports
+// 包含签名费、收款方关联账户不存在时的免租押金，以及 from 自身保持免租所需的最低余额
+func (c *SOLClient) QuoteTokenTransferGas(ctx context.Context, network, token, from, to string, amount string) (*big.Int, error) {
+	if _, err := parsePubkey(from); err != nil {
+		return nil, dep.ErrInvalidAddress
+	}
+	toPk, err := parsePubkey(to)
+	if err != nil {
+		return nil, dep.ErrInvalidAddress
+	}
+	mintPk, err := parsePubkey(token)
+	if err != nil {
+		return nil, dep.ErrInvalidAddress
+	}
+	cli, err := c.pick(network)
+	if err != nil {
+		return nil, err
+	}
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+
+	mint, err := c.mintInfo(ctx2, cli, token)
+	if err != nil {
+		return nil, err
+	}
+	destATA, err := findAssociatedTokenAddress(toPk, mintPk, mustPubkey(mint.Program))
+	if err != nil {
+		return nil, err
+	}
+
+	required, err := c.rentExemptMinimum(ctx2, cli, 0)
+	if err != nil {
+		return nil, err
+	}
+	required += lamportsPerSignature
+
+	exists, err := c.accountExists(ctx2, cli, destATA.String())
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		size := tokenAccountSize
+		if mint.Program == token2022ProgramID {
+			size = token2022AccountSize
+		}
+		rent, err := c.rentExemptMinimum(ctx2, cli, size)
+		if err != nil {
+			return nil, err
+		}
+		required += rent
+	}
+	return new(big.Int).SetUint64(required), nil
+}
+
+func (c *SOLClient) rentExemptMinimum(ctx context.Context, cli *rpcClient, size int) (uint64, error) {
+	result, err := cli.callRPC(ctx, "getMinimumBalanceForRentExemption", []interface{}{size})
+	if err != nil {
+		return 0, err
+	}
+	var lamports uint64
+	if err := json.Unmarshal(result, &lamports); err != nil {
+		return 0, fmt.Errorf("unmarshal rent exemption: %w", err)
+	}
+	return lamports, nil
+}
+
+var _ dep.GasQuoter = (*SOLClient)(nil)
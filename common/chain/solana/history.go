@@ -0,0 +1,90 @@
+package solana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// getSignaturesForAddress 单页上限
+const signaturePage = 1000
+
+// AddressSlots 通过 getSignaturesForAddress 分页读取地址在 [from, to] 内执行成功的交易所在 slot，to 为 0 表示不限
+// 结果按 slot 倒序且去重，同一 slot 只返回一次
+func (c *SOLClient) AddressSlots(ctx context.Context, network, address string, from, to uint64) ([]uint64, error) {
+	cli, err := c.pick(network)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []uint64
+	seen := make(map[uint64]bool)
+	before := ""
+	for {
+		opts := map[string]interface{}{"limit": signaturePage, "commitment": "finalized"}
+		if before != "" {
+			opts["before"] = before
+		}
+		ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+		result, err := cli.callRPC(ctx2, "getSignaturesForAddress", []interface{}{address, opts})
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+		var page []struct {
+			Signature string          `json:"signature"`
+			Slot      uint64          `json:"slot"`
+			Err       json.RawMessage `json:"err"`
+		}
+		if err := json.Unmarshal(result, &page); err != nil {
+			return nil, fmt.Errorf("unmarshal signatures: %w", err)
+		}
+
+		for _, p := range page {
+			if p.Slot < from {
+				return out, nil
+			}
+			if (to > 0 && p.Slot > to) || (len(p.Err) > 0 && string(p.Err) != "null") || seen[p.Slot] {
+				continue
+			}
+			seen[p.Slot] = true
+			out = append(out, p.Slot)
+		}
+		if len(page) < signaturePage {
+			return out, nil
+		}
+		before = page[len(page)-1].Signature
+	}
+}
+
+// TokenAccounts 地址持有的 mint 对应的全部 Token 账户，含非关联账户
+func (c *SOLClient) TokenAccounts(ctx context.Context, network, owner, mint string) ([]string, error) {
+	cli, err := c.pick(network)
+	if err != nil {
+		return nil, err
+	}
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+
+	result, err := cli.callRPC(ctx2, "getTokenAccountsByOwner", []interface{}{
+		owner,
+		map[string]interface{}{"mint": mint},
+		map[string]interface{}{"encoding": "base64", "commitment": "finalized", "dataSlice": map[string]int{"offset": 0, "length": 0}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Value []struct {
+			Pubkey string `json:"pubkey"`
+		} `json:"value"`
+	}
+	if err := json.Unmarshal(result, &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal token accounts: %w", err)
+	}
+	out := make([]string, 0, len(resp.Value))
+	for _, v := range resp.Value {
+		out = append(out, v.Pubkey)
+	}
+	return out, nil
+}
@@ -0,0 +1,199 @@
+package solana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/reguluswee/walletus/common/chain/dep"
+	"github.com/reguluswee/walletus/common/config"
+)
+
+// Receipt 交易回执，费用单位为 lamports
+type Receipt struct {
+	Signature     string
+	Slot          uint64
+	BlockTime     int64
+	Success       bool
+	Err           string // meta.err 原文，成功时为空
+	Fee           uint64
+	Transfers     []Transfer // 解析出的 SOL 与 SPL 转账，只在执行成功时返回
+	TokenBalances []TokenBalanceChange
+}
+
+// TokenBalanceChange 交易前后一个 Token 账户的余额，交易中创建或关闭的账户一侧为 0
+type TokenBalanceChange struct {
+	Account string
+	Mint    string
+	Owner   string
+	Pre     *big.Int
+	Post    *big.Int
+}
+
+type rpcTx struct {
+	Slot      uint64 `json:"slot"`
+	BlockTime *int64 `json:"blockTime"`
+	rpcBlockTx
+}
+
+// GetTransaction 按 anchor 的 commitment（为空时取链配置）读取交易并转换为链无关的回执
+// 交易尚未达到该 commitment 时通过 getSignatureStatuses 区分 pending 与不存在
+func (c *SOLClient) GetTransaction(ctx context.Context, network string, txHash string, anchor dep.AnchorRef) (*dep.TxReceipt, error) {
+	commitment := anchor.Tag
+	if commitment == "" {
+		if cc := config.GetRpcConfig(network); cc != nil {
+			commitment = cc.Consistency
+		}
+	}
+	r, err := c.Transaction(ctx, network, txHash, commitment)
+	if err != nil {
+		return nil, err
+	}
+	out := r.TxReceipt(txHash)
+	if r == nil {
+		seen, err := c.signatureSeen(ctx, network, txHash)
+		if err != nil {
+			return nil, err
+		}
+		if seen {
+			out.Status = dep.TxStatusPending
+		}
+	}
+	out.SetAnchor(anchor)
+	return out, nil
+}
+
+// TxReceipt 转换为链无关的回执，r 为 nil 表示交易不存在
+func (r *Receipt) TxReceipt(signature string) *dep.TxReceipt {
+	out := &dep.TxReceipt{TxHash: signature, Status: dep.TxStatusNotFound}
+	if r == nil {
+		return out
+	}
+	out.Status = dep.TxStatusSuccess
+	if !r.Success {
+		out.Status = dep.TxStatusFailed
+		out.Error = r.Err
+	}
+	out.BlockHeight = r.Slot
+	out.BlockTime = r.BlockTime
+	out.Fee = new(big.Int).SetUint64(r.Fee)
+	for _, t := range r.Transfers {
+		out.Transfers = append(out.Transfers, dep.TxTransfer{Token: t.Token, From: t.From, To: t.To, Amount: t.Amount})
+	}
+	return out
+}
+
+// signatureSeen 节点是否已见到该签名（含 processed），只查询近期状态缓存
+func (c *SOLClient) signatureSeen(ctx context.Context, network, signature string) (bool, error) {
+	cli, err := c.pick(network)
+	if err != nil {
+		return false, err
+	}
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+
+	result, err := cli.callRPC(ctx2, "getSignatureStatuses", []interface{}{[]string{signature}})
+	if err != nil {
+		return false, err
+	}
+	var resp struct {
+		Value []json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(result, &resp); err != nil {
+		return false, fmt.Errorf("unmarshal signature statuses: %w", err)
+	}
+	return len(resp.Value) > 0 && string(resp.Value[0]) != "null", nil
+}
+
+// Transaction 以 jsonParsed 调用 getTransaction，commitment 为 finalized 以外的值时按 confirmed 查询
+func (c *SOLClient) Transaction(ctx context.Context, network, signature, commitment string) (*Receipt, error) {
+	cli, err := c.pick(network)
+	if err != nil {
+		return nil, err
+	}
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+
+	// getTransaction 不支持 processed
+	if commitment != "finalized" {
+		commitment = "confirmed"
+	}
+	result, err := cli.callRPC(ctx2, "getTransaction", []interface{}{
+		signature,
+		map[string]interface{}{
+			"encoding":                       "jsonParsed",
+			"maxSupportedTransactionVersion": 0,
+			"commitment":                     commitment,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if string(result) == "null" {
+		return nil, nil
+	}
+	var raw rpcTx
+	if err := json.Unmarshal(result, &raw); err != nil {
+		return nil, fmt.Errorf("unmarshal transaction: %w", err)
+	}
+	return decodeReceipt(signature, &raw), nil
+}
+
+func decodeReceipt(signature string, raw *rpcTx) *Receipt {
+	r := &Receipt{Signature: signature, Slot: raw.Slot}
+	if raw.BlockTime != nil {
+		r.BlockTime = *raw.BlockTime
+	}
+	meta := raw.Meta
+	if meta == nil {
+		return r
+	}
+	r.Fee = meta.Fee
+	r.Success = len(meta.Err) == 0 || string(meta.Err) == "null"
+	if !r.Success {
+		r.Err = string(meta.Err)
+	}
+	r.Transfers = decodeTransfers(&raw.rpcBlockTx)
+	r.TokenBalances = tokenBalanceChanges(raw.Transaction.Message.AccountKeys, meta.PreTokenBalances, meta.PostTokenBalances)
+	return r
+}
+
+// tokenBalanceChanges 按账户合并交易前后的 Token 余额，顺序与账户在交易中的位置一致
+func tokenBalanceChanges(keys []rpcAccountKey, pre, post []rpcTokenBalance) []TokenBalanceChange {
+	byIndex := make(map[int]*TokenBalanceChange)
+	var order []int
+	merge := func(balances []rpcTokenBalance, isPost bool) {
+		for _, tb := range balances {
+			if tb.AccountIndex < 0 || tb.AccountIndex >= len(keys) {
+				continue
+			}
+			ch, ok := byIndex[tb.AccountIndex]
+			if !ok {
+				ch = &TokenBalanceChange{Account: keys[tb.AccountIndex].Pubkey, Pre: new(big.Int), Post: new(big.Int)}
+				byIndex[tb.AccountIndex] = ch
+				order = append(order, tb.AccountIndex)
+			}
+			ch.Mint, ch.Owner = tb.Mint, tb.Owner
+			amount, ok := new(big.Int).SetString(tb.UiTokenAmount.Amount, 10)
+			if !ok {
+				continue
+			}
+			if isPost {
+				ch.Post = amount
+			} else {
+				ch.Pre = amount
+			}
+		}
+	}
+	merge(pre, false)
+	merge(post, true)
+	slices.Sort(order)
+
+	out := make([]TokenBalanceChange, 0, len(order))
+	for _, idx := range order {
+		out = append(out, *byIndex[idx])
+	}
+	return out
+}
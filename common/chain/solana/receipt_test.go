@@ -0,0 +1,35 @@
+package solana
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDecodeReceipt(t *testing.T) {
+	var raw rpcTx
+	fixture := `{"slot": 42, "blockTime": 1700000000,` + strings.TrimPrefix(blockTxFixture, "{")
+	if err := json.Unmarshal([]byte(fixture), &raw); err != nil {
+		t.Fatal(err)
+	}
+	r := decodeReceipt("sig1", &raw)
+	if !r.Success || r.Slot != 42 || r.BlockTime != 1700000000 || len(r.Transfers) != 3 {
+		t.Fatalf("unexpected receipt %+v", r)
+	}
+	if len(r.TokenBalances) != 2 {
+		t.Fatalf("got %d token balances, want 2", len(r.TokenBalances))
+	}
+	src, dst := r.TokenBalances[0], r.TokenBalances[1]
+	if src.Account != "SrcATA" || src.Pre.String() != "9000000" || src.Post.String() != "6000000" {
+		t.Fatalf("unexpected source balance %+v", src)
+	}
+	if dst.Account != "DstATA" || dst.Owner != "Wallet" || dst.Pre.Sign() != 0 || dst.Post.String() != "3000000" {
+		t.Fatalf("unexpected destination balance %+v", dst)
+	}
+
+	raw.Meta.Err = json.RawMessage(`{"InstructionError":[0,"Custom"]}`)
+	r = decodeReceipt("sig1", &raw)
+	if r.Success || r.Err == "" || len(r.Transfers) != 0 {
+		t.Fatalf("failed transaction should carry no transfers: %+v", r)
+	}
+}
@@ -0,0 +1,231 @@
+package solana
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+
+	"github.com/reguluswee/walletus/common/bip"
+	"github.com/reguluswee/walletus/common/chain/dep"
+)
+
+// SignTransferNative 构建 SystemProgram 转账并签名，from 同时作为手续费支付方
+func (c *SOLClient) SignTransferNative(ctx context.Context, network string, from, to string, amount string, opts any) ([]byte, string, error) {
+	o, err := dep.ToSignOptions(opts)
+	if err != nil {
+		return nil, "", err
+	}
+	lamports, err := parseU64Amount(amount)
+	if err != nil {
+		return nil, "", err
+	}
+	fromPk, err := parsePubkey(from)
+	if err != nil {
+		return nil, "", dep.ErrInvalidAddress
+	}
+	toPk, err := parsePubkey(to)
+	if err != nil {
+		return nil, "", dep.ErrInvalidAddress
+	}
+	priv, err := signingKey(from, o.Key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	cli, err := c.pick(network)
+	if err != nil {
+		return nil, "", err
+	}
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+
+	blockhash, err := c.latestBlockhash(ctx2, cli)
+	if err != nil {
+		return nil, "", err
+	}
+	msg, numSigners, err := compileMessage(fromPk, []instruction{systemTransferIx(fromPk, toPk, lamports)}, blockhash)
+	if err != nil {
+		return nil, "", err
+	}
+	return signMessage(msg, numSigners, priv)
+}
+
+// SignTransferToken 构建 SPL TransferChecked 并签名
+// 收款方的关联 Token 账户不存在时，会在同一笔交易中由 from 付费创建
+func (c *SOLClient) SignTransferToken(ctx context.Context, network, token, from, to string, amount string, opts any) ([]byte, string, error) {
+	o, err := dep.ToSignOptions(opts)
+	if err != nil {
+		return nil, "", err
+	}
+	value, err := parseU64Amount(amount)
+	if err != nil {
+		return nil, "", err
+	}
+	fromPk, err := parsePubkey(from)
+	if err != nil {
+		return nil, "", dep.ErrInvalidAddress
+	}
+	toPk, err := parsePubkey(to)
+	if err != nil {
+		return nil, "", dep.ErrInvalidAddress
+	}
+	mintPk, err := parsePubkey(token)
+	if err != nil {
+		return nil, "", dep.ErrInvalidAddress
+	}
+	priv, err := signingKey(from, o.Key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	cli, err := c.pick(network)
+	if err != nil {
+		return nil, "", err
+	}
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+
+	mint, err := c.mintInfo(ctx2, cli, token)
+	if err != nil {
+		return nil, "", err
+	}
+	tokenProgram, err := parsePubkey(mint.Program)
+	if err != nil {
+		return nil, "", err
+	}
+	sourceATA, err := findAssociatedTokenAddress(fromPk, mintPk, tokenProgram)
+	if err != nil {
+		return nil, "", err
+	}
+	destATA, err := findAssociatedTokenAddress(toPk, mintPk, tokenProgram)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var ixs []instruction
+	exists, err := c.accountExists(ctx2, cli, destATA.String())
+	if err != nil {
+		return nil, "", err
+	}
+	if !exists {
+		ixs = append(ixs, createATAIdempotentIx(fromPk, destATA, toPk, mintPk, tokenProgram))
+	}
+	ixs = append(ixs, transferCheckedIx(sourceATA, mintPk, destATA, fromPk, tokenProgram, value, mint.Decimals))
+
+	blockhash, err := c.latestBlockhash(ctx2, cli)
+	if err != nil {
+		return nil, "", err
+	}
+	msg, numSigners, err := compileMessage(fromPk, ixs, blockhash)
+	if err != nil {
+		return nil, "", err
+	}
+	return signMessage(msg, numSigners, priv)
+}
+
+// signingKey 按派生路径取出 ed25519 私钥，并校验其地址与 from 一致
+func signingKey(from string, k dep.KeyRef) (ed25519.PrivateKey, error) {
+	enc, err := bip.EncMasterFromKeyRef(k)
+	if err != nil {
+		return nil, err
+	}
+	derived, err := bip.DeriveSOLFromPath(enc, k.Path)
+	if err != nil {
+		return nil, err
+	}
+	if derived.Address != from {
+		return nil, dep.ErrKeyMismatch
+	}
+	return derived.Ed25519Priv, nil
+}
+
+func (c *SOLClient) latestBlockhash(ctx context.Context, cli *rpcClient) (pubkey, error) {
+	params := []interface{}{
+		map[string]interface{}{"commitment": "confirmed"},
+	}
+	result, err := cli.callRPC(ctx, "getLatestBlockhash", params)
+	if err != nil {
+		return pubkey{}, err
+	}
+	var resp struct {
+		Value struct {
+			Blockhash string `json:"blockhash"`
+		} `json:"value"`
+	}
+	if err := json.Unmarshal(result, &resp); err != nil {
+		return pubkey{}, fmt.Errorf("unmarshal blockhash: %w", err)
+	}
+	return parsePubkey(resp.Value.Blockhash)
+}
+
+type mintAccount struct {
+	Program  string
+	Decimals uint8
+}
+
+// mintInfo 读取 mint 所属的 Token 程序（Token / Token-2022）及精度
+func (c *SOLClient) mintInfo(ctx context.Context, cli *rpcClient, mint string) (*mintAccount, error) {
+	params := []interface{}{
+		mint,
+		map[string]interface{}{"encoding": "jsonParsed", "commitment": "confirmed"},
+	}
+	result, err := cli.callRPC(ctx, "getAccountInfo", params)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Value *struct {
+			Owner string `json:"owner"`
+			Data  struct {
+				Parsed struct {
+					Type string `json:"type"`
+					Info struct {
+						Decimals uint8 `json:"decimals"`
+					} `json:"info"`
+				} `json:"parsed"`
+			} `json:"data"`
+		} `json:"value"`
+	}
+	if err := json.Unmarshal(result, &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal mint: %w", err)
+	}
+	if resp.Value == nil || resp.Value.Data.Parsed.Type != "mint" {
+		return nil, fmt.Errorf("mint account not found: %s", mint)
+	}
+	if resp.Value.Owner != tokenProgramID && resp.Value.Owner != token2022ProgramID {
+		return nil, fmt.Errorf("unsupported token program: %s", resp.Value.Owner)
+	}
+	return &mintAccount{Program: resp.Value.Owner, Decimals: resp.Value.Data.Parsed.Info.Decimals}, nil
+}
+
+func (c *SOLClient) accountExists(ctx context.Context, cli *rpcClient, addr string) (bool, error) {
+	params := []interface{}{
+		addr,
+		map[string]interface{}{"encoding": "base64", "commitment": "confirmed"},
+	}
+	result, err := cli.callRPC(ctx, "getAccountInfo", params)
+	if err != nil {
+		return false, err
+	}
+	var resp struct {
+		Value *json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(result, &resp); err != nil {
+		return false, fmt.Errorf("unmarshal account: %w", err)
+	}
+	return resp.Value != nil, nil
+}
+
+func parseU64Amount(amount string) (uint64, error) {
+	v, err := dep.ParseAmount(amount)
+	if err != nil {
+		return 0, err
+	}
+	if !v.IsUint64() {
+		return 0, dep.ErrInvalidAmount
+	}
+	return v.Uint64(), nil
+}
+
+var _ dep.Signer = (*SOLClient)(nil)
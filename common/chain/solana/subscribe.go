@@ -0,0 +1,138 @@
+package solana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	wsPingInterval = 20 * time.Second
+	// 超过该时间未收到任何消息（含 pong）视为连接已断开
+	wsReadTimeout = 60 * time.Second
+)
+
+type wsRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int    `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+type wsMessage struct {
+	ID     *int      `json:"id"`
+	Method string    `json:"method"`
+	Error  *RPCError `json:"error"`
+	Params struct {
+		Result json.RawMessage `json:"result"`
+	} `json:"params"`
+}
+
+// Subscribe 通过 WebSocket 订阅 slotSubscribe，并对 mentions 中的每个账户订阅 logsSubscribe
+// 每条通知向 slots 发送对应的 slot；阻塞直到连接断开、订阅出错或 ctx 取消，由调用方负责重连
+func Subscribe(ctx context.Context, wsURL string, mentions []string, commitment string, slots chan<- uint64) error {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, wsURL, nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(wsPingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				// 关闭连接使阻塞的读取返回
+				conn.Close()
+				return
+			case <-done:
+				return
+			case <-ticker.C:
+				_ = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsPingInterval))
+			}
+		}
+	}()
+
+	reqs := []wsRequest{{JSONRPC: "2.0", ID: 1, Method: "slotSubscribe", Params: []any{}}}
+	for i, m := range mentions {
+		reqs = append(reqs, wsRequest{
+			JSONRPC: "2.0",
+			ID:      i + 2,
+			Method:  "logsSubscribe",
+			Params: []any{
+				map[string]any{"mentions": []string{m}},
+				map[string]any{"commitment": getCommitmentFromTag(commitment)},
+			},
+		})
+	}
+	for _, r := range reqs {
+		if err := conn.WriteJSON(r); err != nil {
+			return err
+		}
+	}
+
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsReadTimeout))
+	})
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(wsReadTimeout))
+		var msg wsMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		if msg.Error != nil {
+			return fmt.Errorf("subscribe %d: %w", derefID(msg.ID), msg.Error)
+		}
+
+		slot, ok := notificationSlot(msg.Method, msg.Params.Result)
+		if !ok {
+			continue
+		}
+		select {
+		case slots <- slot:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// notificationSlot 取出 slotNotification 与 logsNotification 对应的 slot
+func notificationSlot(method string, result json.RawMessage) (uint64, bool) {
+	switch method {
+	case "slotNotification":
+		var r struct {
+			Slot uint64 `json:"slot"`
+		}
+		if json.Unmarshal(result, &r) != nil {
+			return 0, false
+		}
+		return r.Slot, true
+	case "logsNotification":
+		var r struct {
+			Context struct {
+				Slot uint64 `json:"slot"`
+			} `json:"context"`
+		}
+		if json.Unmarshal(result, &r) != nil {
+			return 0, false
+		}
+		return r.Context.Slot, true
+	}
+	return 0, false
+}
+
+func derefID(id *int) int {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
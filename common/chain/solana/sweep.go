@@ -0,0 +1,99 @@
+package solana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/reguluswee/walletus/common/chain/dep"
+)
+
+// BuildAndSignSweep 将 from 上的全部余额归集到 dest
+// SOL 归集扣除单签名手续费后全部转出，账户余额归零后由链上回收
+// SPL 归集转出 from 关联 Token 账户上的全部余额，手续费由 from 的 SOL 支付
+func (c *SOLClient) BuildAndSignSweep(ctx context.Context, network string, from string, dest string, opts any) ([]byte, string, string, error) {
+	o, err := dep.ToSweepOptions(opts)
+	if err != nil {
+		return nil, "", "", err
+	}
+	fromPk, err := parsePubkey(from)
+	if err != nil {
+		return nil, "", "", dep.ErrInvalidAddress
+	}
+	cli, err := c.pick(network)
+	if err != nil {
+		return nil, "", "", err
+	}
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+
+	if o.Token != "" {
+		mintPk, err := parsePubkey(o.Token)
+		if err != nil {
+			return nil, "", "", dep.ErrInvalidAddress
+		}
+		mint, err := c.mintInfo(ctx2, cli, o.Token)
+		if err != nil {
+			return nil, "", "", err
+		}
+		ata, err := findAssociatedTokenAddress(fromPk, mintPk, mustPubkey(mint.Program))
+		if err != nil {
+			return nil, "", "", err
+		}
+		bal, err := c.tokenAccountBalance(ctx2, cli, ata.String())
+		if err != nil {
+			return nil, "", "", err
+		}
+		if bal.Sign() <= 0 {
+			return nil, "", "", dep.ErrNothingToSweep
+		}
+		raw, hash, err := c.SignTransferToken(ctx, network, o.Token, from, dest, bal.String(), &o.SignOptions)
+		return raw, hash, bal.String(), err
+	}
+
+	nb, err := c.getAccountBalance(ctx2, cli, from, "confirmed")
+	if err != nil {
+		return nil, "", "", err
+	}
+	amount := new(big.Int).Sub(nb.Amount, big.NewInt(lamportsPerSignature))
+	if amount.Sign() <= 0 {
+		return nil, "", "", dep.ErrNothingToSweep
+	}
+	raw, hash, err := c.SignTransferNative(ctx, network, from, dest, amount.String(), &o.SignOptions)
+	return raw, hash, amount.String(), err
+}
+
+// tokenAccountBalance 读取 Token 账户余额，账户不存在时返回 0
+func (c *SOLClient) tokenAccountBalance(ctx context.Context, cli *rpcClient, account string) (*big.Int, error) {
+	exists, err := c.accountExists(ctx, cli, account)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return big.NewInt(0), nil
+	}
+	params := []interface{}{
+		account,
+		map[string]interface{}{"commitment": "confirmed"},
+	}
+	result, err := cli.callRPC(ctx, "getTokenAccountBalance", params)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Value struct {
+			Amount string `json:"amount"`
+		} `json:"value"`
+	}
+	if err := json.Unmarshal(result, &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal token balance: %w", err)
+	}
+	v, ok := new(big.Int).SetString(resp.Value.Amount, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid token amount: %s", resp.Value.Amount)
+	}
+	return v, nil
+}
+
+var _ dep.Sweeper = (*SOLClient)(nil)
@@ -0,0 +1,235 @@
+package solana
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"filippo.io/edwards25519"
+	"github.com/mr-tron/base58"
+)
+
+const (
+	systemProgramID    = "11111111111111111111111111111111"
+	tokenProgramID     = "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA"
+	token2022ProgramID = "TokenzQdBNbLqP5VEhdkAS6EPFLC1PHnBqCXEpPxuEb"
+	ataProgramID       = "ATokenGPvbdGVxr1b2hvZbsiqW5xWH25efTNsLJA8knL"
+)
+
+// 每个签名固定收取的基础费用（lamports）
+const lamportsPerSignature = 5000
+
+type pubkey [32]byte
+
+func parsePubkey(s string) (pubkey, error) {
+	var pk pubkey
+	b, err := base58.Decode(s)
+	if err != nil || len(b) != 32 {
+		return pk, fmt.Errorf("invalid pubkey: %s", s)
+	}
+	copy(pk[:], b)
+	return pk, nil
+}
+
+func mustPubkey(s string) pubkey {
+	pk, err := parsePubkey(s)
+	if err != nil {
+		panic(err)
+	}
+	return pk
+}
+
+func (p pubkey) String() string {
+	return base58.Encode(p[:])
+}
+
+type accountMeta struct {
+	Pubkey     pubkey
+	IsSigner   bool
+	IsWritable bool
+}
+
+type instruction struct {
+	ProgramID pubkey
+	Accounts  []accountMeta
+	Data      []byte
+}
+
+// systemTransferIx SystemProgram::Transfer
+func systemTransferIx(from, to pubkey, lamports uint64) instruction {
+	data := make([]byte, 12)
+	binary.LittleEndian.PutUint32(data[0:4], 2)
+	binary.LittleEndian.PutUint64(data[4:12], lamports)
+	return instruction{
+		ProgramID: mustPubkey(systemProgramID),
+		Accounts: []accountMeta{
+			{Pubkey: from, IsSigner: true, IsWritable: true},
+			{Pubkey: to, IsWritable: true},
+		},
+		Data: data,
+	}
+}
+
+// createATAIdempotentIx AssociatedTokenAccount::CreateIdempotent，账户已存在时不会失败
+func createATAIdempotentIx(payer, ata, owner, mint, tokenProgram pubkey) instruction {
+	return instruction{
+		ProgramID: mustPubkey(ataProgramID),
+		Accounts: []accountMeta{
+			{Pubkey: payer, IsSigner: true, IsWritable: true},
+			{Pubkey: ata, IsWritable: true},
+			{Pubkey: owner},
+			{Pubkey: mint},
+			{Pubkey: mustPubkey(systemProgramID)},
+			{Pubkey: tokenProgram},
+		},
+		Data: []byte{1},
+	}
+}
+
+// transferCheckedIx Token::TransferChecked
+func transferCheckedIx(source, mint, dest, owner, tokenProgram pubkey, amount uint64, decimals uint8) instruction {
+	data := make([]byte, 10)
+	data[0] = 12
+	binary.LittleEndian.PutUint64(data[1:9], amount)
+	data[9] = decimals
+	return instruction{
+		ProgramID: tokenProgram,
+		Accounts: []accountMeta{
+			{Pubkey: source, IsWritable: true},
+			{Pubkey: mint},
+			{Pubkey: dest, IsWritable: true},
+			{Pubkey: owner, IsSigner: true},
+		},
+		Data: data,
+	}
+}
+
+// compileMessage 编译 legacy 消息，账户排序：可写签名者、只读签名者、可写非签名者、只读非签名者
+func compileMessage(feePayer pubkey, ixs []instruction, recentBlockhash pubkey) ([]byte, int, error) {
+	type entry struct {
+		meta  accountMeta
+		order int
+	}
+	metas := map[pubkey]*entry{}
+	var keys []pubkey
+	add := func(m accountMeta) {
+		if e, ok := metas[m.Pubkey]; ok {
+			e.meta.IsSigner = e.meta.IsSigner || m.IsSigner
+			e.meta.IsWritable = e.meta.IsWritable || m.IsWritable
+			return
+		}
+		metas[m.Pubkey] = &entry{meta: m, order: len(keys)}
+		keys = append(keys, m.Pubkey)
+	}
+	add(accountMeta{Pubkey: feePayer, IsSigner: true, IsWritable: true})
+	for _, ix := range ixs {
+		for _, a := range ix.Accounts {
+			add(a)
+		}
+		add(accountMeta{Pubkey: ix.ProgramID})
+	}
+
+	var groups [4][]pubkey
+	for _, k := range keys {
+		m := metas[k].meta
+		switch {
+		case m.IsSigner && m.IsWritable:
+			groups[0] = append(groups[0], k)
+		case m.IsSigner:
+			groups[1] = append(groups[1], k)
+		case m.IsWritable:
+			groups[2] = append(groups[2], k)
+		default:
+			groups[3] = append(groups[3], k)
+		}
+	}
+	var ordered []pubkey
+	for _, g := range groups {
+		ordered = append(ordered, g...)
+	}
+	if len(ordered) > 255 {
+		return nil, 0, errors.New("too many accounts")
+	}
+	index := make(map[pubkey]byte, len(ordered))
+	for i, k := range ordered {
+		index[k] = byte(i)
+	}
+
+	numSigners := len(groups[0]) + len(groups[1])
+	msg := []byte{byte(numSigners), byte(len(groups[1])), byte(len(groups[3]))}
+	msg = appendCompactU16(msg, len(ordered))
+	for _, k := range ordered {
+		msg = append(msg, k[:]...)
+	}
+	msg = append(msg, recentBlockhash[:]...)
+	msg = appendCompactU16(msg, len(ixs))
+	for _, ix := range ixs {
+		msg = append(msg, index[ix.ProgramID])
+		msg = appendCompactU16(msg, len(ix.Accounts))
+		for _, a := range ix.Accounts {
+			msg = append(msg, index[a.Pubkey])
+		}
+		msg = appendCompactU16(msg, len(ix.Data))
+		msg = append(msg, ix.Data...)
+	}
+	return msg, numSigners, nil
+}
+
+// signMessage 对消息签名并序列化为完整交易，signers 需覆盖消息中的全部签名者
+func signMessage(msg []byte, numSigners int, signers ...ed25519.PrivateKey) ([]byte, string, error) {
+	if len(signers) != numSigners {
+		return nil, "", fmt.Errorf("expect %d signers, got %d", numSigners, len(signers))
+	}
+	out := appendCompactU16(nil, numSigners)
+	var first []byte
+	for _, priv := range signers {
+		sig := ed25519.Sign(priv, msg)
+		if first == nil {
+			first = sig
+		}
+		out = append(out, sig...)
+	}
+	out = append(out, msg...)
+	return out, base58.Encode(first), nil
+}
+
+func appendCompactU16(b []byte, n int) []byte {
+	for {
+		elem := byte(n & 0x7f)
+		n >>= 7
+		if n == 0 {
+			return append(b, elem)
+		}
+		b = append(b, elem|0x80)
+	}
+}
+
+// findAssociatedTokenAddress 计算 owner 在 mint 下的关联 Token 账户
+func findAssociatedTokenAddress(owner, mint, tokenProgram pubkey) (pubkey, error) {
+	return findProgramAddress([][]byte{owner[:], tokenProgram[:], mint[:]}, mustPubkey(ataProgramID))
+}
+
+func findProgramAddress(seeds [][]byte, program pubkey) (pubkey, error) {
+	for bump := 255; bump >= 0; bump-- {
+		h := sha256.New()
+		for _, s := range seeds {
+			h.Write(s)
+		}
+		h.Write([]byte{byte(bump)})
+		h.Write(program[:])
+		h.Write([]byte("ProgramDerivedAddress"))
+		var pk pubkey
+		copy(pk[:], h.Sum(nil))
+		if !isOnCurve(pk) {
+			return pk, nil
+		}
+	}
+	return pubkey{}, errors.New("unable to find a viable program address")
+}
+
+func isOnCurve(pk pubkey) bool {
+	_, err := new(edwards25519.Point).SetBytes(pk[:])
+	return err == nil
+}
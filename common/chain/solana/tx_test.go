@@ -0,0 +1,71 @@
+package solana
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/mr-tron/base58"
+)
+
+func TestAppendCompactU16(t *testing.T) {
+	cases := map[int][]byte{
+		0:     {0x00},
+		127:   {0x7f},
+		128:   {0x80, 0x01},
+		16383: {0xff, 0x7f},
+		16384: {0x80, 0x80, 0x01},
+	}
+	for n, want := range cases {
+		if got := appendCompactU16(nil, n); !bytes.Equal(got, want) {
+			t.Fatalf("compact-u16(%d) = %x, want %x", n, got, want)
+		}
+	}
+}
+
+func TestCompileTransferMessage(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	var from, to, blockhash pubkey
+	copy(from[:], pub)
+	to[0] = 7
+	blockhash[0] = 9
+
+	msg, numSigners, err := compileMessage(from, []instruction{systemTransferIx(from, to, 1000)}, blockhash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if numSigners != 1 {
+		t.Fatalf("numSigners = %d", numSigners)
+	}
+	// header: 1 个签名者，0 个只读签名者，1 个只读非签名者（system program）
+	if !bytes.Equal(msg[:4], []byte{1, 0, 1, 3}) {
+		t.Fatalf("unexpected header %x", msg[:4])
+	}
+	if !bytes.Equal(msg[4:36], from[:]) || !bytes.Equal(msg[36:68], to[:]) {
+		t.Fatal("account order mismatch")
+	}
+
+	raw, sig, err := signMessage(msg, numSigners, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigBytes, _ := base58.Decode(sig)
+	if !bytes.Equal(raw[1:65], sigBytes) || !ed25519.Verify(pub, raw[65:], sigBytes) {
+		t.Fatal("signature does not verify")
+	}
+}
+
+func TestFindAssociatedTokenAddressOffCurve(t *testing.T) {
+	owner := mustPubkey("GXyzievGa9eBXGRhBxjUUP55mAhF5W37pu6WKqnvGkrv")
+	mint := mustPubkey("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v")
+	ata, err := findAssociatedTokenAddress(owner, mint, mustPubkey(tokenProgramID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isOnCurve(ata) {
+		t.Fatal("associated token address must be off curve")
+	}
+	if !isOnCurve(owner) {
+		t.Fatal("wallet address should be on curve")
+	}
+}
@@ -0,0 +1,270 @@
+package tron
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/mr-tron/base58"
+)
+
+const (
+	// getblockbylimitnext 单次最多返回 100 个区块
+	maxBlocksPerCall = 100
+
+	blockTimeout = 15 * time.Second
+)
+
+var (
+	trc20TransferID     = trc20Selector(trc20TransferSelector)
+	trc20TransferFromID = trc20Selector("transferFrom(address,address,uint256)")
+)
+
+// Block 扫描用的区块，Transfers 为已解码的成功转账
+type Block struct {
+	Number     uint64
+	Hash       string
+	ParentHash string
+	Timestamp  int64
+	Transfers  []Transfer
+}
+
+// Transfer 一笔 TRX 或 TRC-20 转账，地址均为 base58，Token 为空表示 TRX
+type Transfer struct {
+	TxID   string
+	Token  string
+	From   string
+	To     string
+	Amount *big.Int
+}
+
+type rpcBlock struct {
+	BlockID     string `json:"blockID"`
+	BlockHeader struct {
+		RawData struct {
+			Number     uint64 `json:"number"`
+			Timestamp  int64  `json:"timestamp"`
+			ParentHash string `json:"parentHash"`
+		} `json:"raw_data"`
+	} `json:"block_header"`
+	Transactions []rpcBlockTx `json:"transactions"`
+}
+
+type rpcBlockTx struct {
+	TxID    string     `json:"txID"`
+	Ret     []rpcTxRet `json:"ret"`
+	RawData struct {
+		Contract []rpcContract `json:"contract"`
+	} `json:"raw_data"`
+}
+
+type rpcTxRet struct {
+	ContractRet string `json:"contractRet"`
+}
+
+type rpcContract struct {
+	Type      string `json:"type"`
+	Parameter struct {
+		Value rpcContractValue `json:"value"`
+	} `json:"parameter"`
+}
+
+// rpcContractValue TransferContract 与 TriggerSmartContract 的参数，地址为 41 前缀的 hex
+type rpcContractValue struct {
+	OwnerAddress    string `json:"owner_address"`
+	ToAddress       string `json:"to_address"`
+	Amount          int64  `json:"amount"`
+	ContractAddress string `json:"contract_address"`
+	Data            string `json:"data"`
+}
+
+// BlockNumber 最新区块高度，solid 为 true 时读取固化节点的已确认高度
+func (c *TRXClient) BlockNumber(ctx context.Context, network string, solid bool) (uint64, error) {
+	cli, err := c.pick(network)
+	if err != nil {
+		return 0, err
+	}
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+	return nowBlockNumber(ctx2, cli, solid)
+}
+
+func nowBlockNumber(ctx context.Context, cli *httpClient, solid bool) (uint64, error) {
+	method := "wallet/getnowblock"
+	if solid {
+		method = "walletsolidity/getnowblock"
+	}
+	var b rpcBlock
+	if err := cli.callRPCInto(ctx, method, nil, &b); err != nil {
+		return 0, err
+	}
+	if b.BlockID == "" {
+		return 0, fmt.Errorf("empty block from %s", method)
+	}
+	return b.BlockHeader.RawData.Number, nil
+}
+
+// BlockByNumber 通过 wallet/getblockbynum 读取单个区块
+func (c *TRXClient) BlockByNumber(ctx context.Context, network string, number uint64) (*Block, error) {
+	cli, err := c.pick(network)
+	if err != nil {
+		return nil, err
+	}
+	ctx2, cancel := context.WithTimeout(ctx, blockTimeout)
+	defer cancel()
+
+	var b rpcBlock
+	if err := cli.callRPCInto(ctx2, "wallet/getblockbynum", map[string]interface{}{"num": number}, &b); err != nil {
+		return nil, err
+	}
+	if b.BlockID == "" {
+		return nil, fmt.Errorf("block %d not found on %s", number, network)
+	}
+	return decodeBlock(&b), nil
+}
+
+// BlocksByRange 通过 wallet/getblockbylimitnext 读取 [from, to] 的区块，按高度升序
+// 节点漏返的高度逐个用 getblockbynum 补齐
+func (c *TRXClient) BlocksByRange(ctx context.Context, network string, from, to uint64) ([]*Block, error) {
+	cli, err := c.pick(network)
+	if err != nil {
+		return nil, err
+	}
+
+	byNum := make(map[uint64]*Block, to-from+1)
+	for start := from; start <= to; start += maxBlocksPerCall {
+		end := min(start+maxBlocksPerCall-1, to)
+		ctx2, cancel := context.WithTimeout(ctx, blockTimeout)
+		var resp struct {
+			Block []rpcBlock `json:"block"`
+		}
+		// endNum 不含
+		err := cli.callRPCInto(ctx2, "wallet/getblockbylimitnext", map[string]interface{}{"startNum": start, "endNum": end + 1}, &resp)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+		for i := range resp.Block {
+			b := decodeBlock(&resp.Block[i])
+			if b.Number >= start && b.Number <= end {
+				byNum[b.Number] = b
+			}
+		}
+	}
+
+	out := make([]*Block, 0, to-from+1)
+	for n := from; n <= to; n++ {
+		b, ok := byNum[n]
+		if !ok {
+			if b, err = c.BlockByNumber(ctx, network, n); err != nil {
+				return nil, err
+			}
+		}
+		out = append(out, b)
+	}
+	return out, nil
+}
+
+func decodeBlock(b *rpcBlock) *Block {
+	out := &Block{
+		Number:     b.BlockHeader.RawData.Number,
+		Hash:       b.BlockID,
+		ParentHash: b.BlockHeader.RawData.ParentHash,
+		Timestamp:  b.BlockHeader.RawData.Timestamp,
+	}
+	for i := range b.Transactions {
+		if t, ok := decodeTransfer(&b.Transactions[i]); ok {
+			out.Transfers = append(out.Transfers, t)
+		}
+	}
+	return out
+}
+
+// decodeTransfer 解码 TransferContract 及 TRC-20 transfer / transferFrom 调用，只保留执行成功的交易
+func decodeTransfer(tx *rpcBlockTx) (Transfer, bool) {
+	if len(tx.RawData.Contract) != 1 || len(tx.Ret) == 0 || tx.Ret[0].ContractRet != "SUCCESS" {
+		return Transfer{}, false
+	}
+	ct := tx.RawData.Contract[0]
+	v := ct.Parameter.Value
+	from, err := hexToBase58Address(v.OwnerAddress)
+	if err != nil {
+		return Transfer{}, false
+	}
+
+	switch ct.Type {
+	case "TransferContract":
+		to, err := hexToBase58Address(v.ToAddress)
+		if err != nil || v.Amount <= 0 {
+			return Transfer{}, false
+		}
+		return Transfer{TxID: tx.TxID, From: from, To: to, Amount: big.NewInt(v.Amount)}, true
+
+	case "TriggerSmartContract":
+		token, err := hexToBase58Address(v.ContractAddress)
+		if err != nil {
+			return Transfer{}, false
+		}
+		data, err := hex.DecodeString(v.Data)
+		if err != nil || len(data) < 4 {
+			return Transfer{}, false
+		}
+		var toWord, amountWord []byte
+		switch {
+		case bytes.Equal(data[:4], trc20TransferID) && len(data) >= 4+64:
+			toWord, amountWord = data[4:36], data[36:68]
+		case bytes.Equal(data[:4], trc20TransferFromID) && len(data) >= 4+96:
+			from, err = wordToBase58(data[4:36])
+			if err != nil {
+				return Transfer{}, false
+			}
+			toWord, amountWord = data[36:68], data[68:100]
+		default:
+			return Transfer{}, false
+		}
+		to, err := wordToBase58(toWord)
+		if err != nil {
+			return Transfer{}, false
+		}
+		amount := new(big.Int).SetBytes(amountWord)
+		if amount.Sign() <= 0 {
+			return Transfer{}, false
+		}
+		return Transfer{TxID: tx.TxID, Token: token, From: from, To: to, Amount: amount}, true
+	}
+	return Transfer{}, false
+}
+
+// wordToBase58 ABI 编码的 address 参数（32 字节，低 20 字节为地址）转 base58
+func wordToBase58(word []byte) (string, error) {
+	return hexToBase58Address(hex.EncodeToString(word[12:32]))
+}
+
+// hexToBase58Address hex 地址（41 前缀 21 字节或 20 字节）转 base58check 地址，与 base58AddressToHex 互逆
+func hexToBase58Address(h string) (string, error) {
+	raw, err := hex.DecodeString(strings.TrimPrefix(h, "0x"))
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case len(raw) == 20:
+		raw = tronAddr(raw)
+	case len(raw) == 21 && raw[0] == 0x41:
+	default:
+		return "", fmt.Errorf("invalid tron address: %s", h)
+	}
+	sum := sha256.Sum256(raw)
+	sum = sha256.Sum256(sum[:])
+	return base58.Encode(append(raw, sum[:4]...)), nil
+}
+
+// ValidAddress 校验 base58check 格式的 TRON 地址
+func ValidAddress(addr string) bool {
+	_, err := base58AddressToHex(addr)
+	return err == nil
+}
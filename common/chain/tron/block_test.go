@@ -0,0 +1,63 @@
+package tron
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+const usdtHex = "41a614f803b6fd780986a42c78ec9c7f77e6ded13c"
+
+func TestHexToBase58Address(t *testing.T) {
+	addr, err := hexToBase58Address(usdtHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr != "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t" {
+		t.Fatalf("got %s", addr)
+	}
+	back, err := base58AddressToHex(addr)
+	if err != nil || "41"+hex.EncodeToString(back) != usdtHex {
+		t.Fatalf("round trip failed: %x %v", back, err)
+	}
+}
+
+func TestDecodeTransfer(t *testing.T) {
+	owner := "41" + "11" + hex.EncodeToString(make([]byte, 19))
+	to := make([]byte, 20)
+	to[19] = 0x22
+	wantTo, _ := hexToBase58Address(hex.EncodeToString(to))
+
+	newTx := func(typ string, v rpcContractValue) *rpcBlockTx {
+		tx := &rpcBlockTx{TxID: "aa", Ret: []rpcTxRet{{ContractRet: "SUCCESS"}}}
+		c := rpcContract{Type: typ}
+		c.Parameter.Value = v
+		tx.RawData.Contract = []rpcContract{c}
+		return tx
+	}
+
+	tr, ok := decodeTransfer(newTx("TransferContract", rpcContractValue{
+		OwnerAddress: owner,
+		ToAddress:    "41" + hex.EncodeToString(to),
+		Amount:       1_500_000,
+	}))
+	if !ok || tr.Token != "" || tr.To != wantTo || tr.Amount.Int64() != 1_500_000 {
+		t.Fatalf("native transfer: %+v %v", tr, ok)
+	}
+
+	data := append(trc20Selector(trc20TransferSelector), encodeTRC20TransferParameter(to, big.NewInt(42))...)
+	token := newTx("TriggerSmartContract", rpcContractValue{
+		OwnerAddress:    owner,
+		ContractAddress: usdtHex,
+		Data:            hex.EncodeToString(data),
+	})
+	tr, ok = decodeTransfer(token)
+	if !ok || tr.Token != "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t" || tr.To != wantTo || tr.Amount.Int64() != 42 {
+		t.Fatalf("trc20 transfer: %+v %v", tr, ok)
+	}
+
+	token.Ret[0].ContractRet = "REVERT"
+	if _, ok := decodeTransfer(token); ok {
+		t.Fatal("reverted transfer should be skipped")
+	}
+}
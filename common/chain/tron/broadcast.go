@@ -0,0 +1,44 @@
+package tron
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/reguluswee/walletus/common/chain/dep"
+)
+
+// Broadcast 将 SignTransfer* 返回的已签名交易 JSON 提交到 wallet/broadcasttransaction
+func (c *TRXClient) Broadcast(ctx context.Context, network string, rawTx []byte) (string, error) {
+	var tx signedTx
+	if err := json.Unmarshal(rawTx, &tx); err != nil {
+		return "", fmt.Errorf("decode raw tx: %w", err)
+	}
+	if tx.TxID == "" || len(tx.Signature) == 0 {
+		return "", fmt.Errorf("raw tx is not a signed transaction")
+	}
+
+	cli, err := c.pick(network)
+	if err != nil {
+		return "", err
+	}
+
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+
+	var resp struct {
+		Result  bool   `json:"result"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		TxID    string `json:"txid"`
+	}
+	if err := cli.callRPCInto(ctx2, "wallet/broadcasttransaction", json.RawMessage(rawTx), &resp); err != nil {
+		return tx.TxID, dep.ClassifyBroadcastError(err.Error())
+	}
+	if !resp.Result {
+		return tx.TxID, dep.ClassifyBroadcastError(resp.Code + ": " + decodeTronMessage(resp.Message))
+	}
+	return tx.TxID, nil
+}
+
+var _ dep.Broadcaster = (*TRXClient)(nil)
@@ -17,15 +17,37 @@ import (
 
 	"github.com/mr-tron/base58"
 	"github.com/reguluswee/walletus/common/chain/dep"
+	"github.com/reguluswee/walletus/common/chain/provider"
 	"github.com/reguluswee/walletus/common/config"
 	"golang.org/x/crypto/sha3"
 	"golang.org/x/sync/singleflight"
 )
 
-type httpClient struct {
+// endpoint 一个 TRON HTTP API 提供商
+type endpoint struct {
 	baseURL string
 	client  *http.Client
-	name    string
+}
+
+// httpClient 一条链上全部提供商的 HTTP API 客户端，每次请求经 provider.Pool 选择端点
+type httpClient struct {
+	pool *provider.Pool[*endpoint]
+}
+
+// statusError 非 200 的 HTTP 响应
+type statusError struct {
+	Code int
+	Body string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("http status %d: %s", e.Code, e.Body)
+}
+
+// 广播不是幂等读取，失败时不换端点重试
+var nonIdempotent = map[string]bool{
+	"wallet/broadcasttransaction": true,
+	"wallet/broadcasthex":         true,
 }
 
 type TRXClient struct {
@@ -77,32 +99,75 @@ func (c *TRXClient) ensureClient(network string) (*httpClient, error) {
 		return nil, fmt.Errorf("missing RPC config for %s", network)
 	}
 
-	// 取第一个可用的 RPC 端点
-	var httpCli *httpClient
-	for _, url := range cc.GetRpc() {
-		baseURL := strings.TrimSuffix(url, "/")
-		httpCli = &httpClient{
-			baseURL: baseURL,
-			client: &http.Client{
-				Timeout: c.reqTimeout,
-			},
-			name: shortAlias(network, url),
-		}
-		// 测试连接（可选，这里简化处理）
-		break
+	pool, err := provider.New(network, cc.GetRpcMapper(), func(url string) (*endpoint, error) {
+		return &endpoint{
+			baseURL: strings.TrimSuffix(url, "/"),
+			client:  &http.Client{Timeout: c.reqTimeout},
+		}, nil
+	}, isEndpointFailure)
+	if err != nil {
+		return nil, err
 	}
 
-	if httpCli == nil {
-		return nil, fmt.Errorf("no RPC available for %s", network)
-	}
+	client = &httpClient{pool: pool}
+	c.clients[network] = client
+	return client, nil
+}
 
-	c.clients[network] = httpCli
-	return httpCli, nil
+// isEndpointFailure 除参数错误类的 4xx 外，请求失败都归咎于端点；TRON 的业务错误以 200 返回，不经过这里
+func isEndpointFailure(err error) bool {
+	var se *statusError
+	if errors.As(err, &se) && se.Code >= 400 && se.Code < 500 {
+		return se.Code == http.StatusTooManyRequests || se.Code == http.StatusRequestTimeout ||
+			se.Code == http.StatusUnauthorized || se.Code == http.StatusForbidden
+	}
+	return true
 }
 
 // callRPC 调用 TRON RPC API
 // TRON API 使用直接 POST JSON 到 /wallet/{method}，请求体是参数对象
 func (cli *httpClient) callRPC(ctx context.Context, method string, params interface{}) (map[string]interface{}, error) {
+	respBody, err := cli.post(ctx, method, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		// 如果不是 JSON，可能是错误字符串
+		return nil, fmt.Errorf("unmarshal response: %w, body: %s", err, string(respBody))
+	}
+
+	// 检查错误字段
+	if errMsg, ok := result["Error"]; ok && errMsg != nil {
+		return nil, fmt.Errorf("rpc error: %v", errMsg)
+	}
+
+	return result, nil
+}
+
+// callRPCInto 调用 TRON RPC API 并将结果解析到 out
+// 交易等需要原样回传的结构应使用 json.RawMessage 承接，避免大数精度丢失
+func (cli *httpClient) callRPCInto(ctx context.Context, method string, params interface{}, out interface{}) error {
+	respBody, err := cli.post(ctx, method, params)
+	if err != nil {
+		return err
+	}
+
+	var errResp struct {
+		Error string `json:"Error"`
+	}
+	if json.Unmarshal(respBody, &errResp) == nil && errResp.Error != "" {
+		return fmt.Errorf("rpc error: %s", errResp.Error)
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("unmarshal response: %w, body: %s", err, string(respBody))
+	}
+	return nil
+}
+
+// post 选择端点发送请求，幂等读取在端点故障时换一个端点重试
+func (cli *httpClient) post(ctx context.Context, method string, params interface{}) ([]byte, error) {
 	var bodyBytes []byte
 	var err error
 
@@ -115,7 +180,16 @@ func (cli *httpClient) callRPC(ctx context.Context, method string, params interf
 		}
 	}
 
-	url := cli.baseURL + "/" + method
+	var respBody []byte
+	err = cli.pool.Do(ctx, 1, !nonIdempotent[method], func(ctx context.Context, e *provider.Endpoint[*endpoint]) error {
+		respBody, err = e.Client.post(ctx, method, bodyBytes)
+		return err
+	})
+	return respBody, err
+}
+
+func (e *endpoint) post(ctx context.Context, method string, bodyBytes []byte) ([]byte, error) {
+	url := e.baseURL + "/" + method
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(bodyBytes))
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
@@ -123,7 +197,7 @@ func (cli *httpClient) callRPC(ctx context.Context, method string, params interf
 
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := cli.client.Do(req)
+	resp, err := e.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("http request: %w", err)
 	}
@@ -136,21 +210,10 @@ func (cli *httpClient) callRPC(ctx context.Context, method string, params interf
 
 	// TRON API 可能返回错误字符串或 JSON 对象
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("http status %d: %s", resp.StatusCode, string(respBody))
-	}
-
-	var result map[string]interface{}
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		// 如果不是 JSON，可能是错误字符串
-		return nil, fmt.Errorf("unmarshal response: %w, body: %s", err, string(respBody))
-	}
-
-	// 检查错误字段
-	if errMsg, ok := result["Error"]; ok && errMsg != nil {
-		return nil, fmt.Errorf("rpc error: %v", errMsg)
+		return nil, &statusError{Code: resp.StatusCode, Body: string(respBody)}
 	}
 
-	return result, nil
+	return respBody, nil
 }
 
 func (c *TRXClient) Anchor(ctx context.Context, network string, cs dep.Consistency) (dep.AnchorRef, error) {
@@ -161,6 +224,7 @@ func (c *TRXClient) Anchor(ctx context.Context, network string, cs dep.Consisten
 
 	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
 	defer cancel()
+	ctx2, served := provider.WithServed(ctx2)
 
 	tag := cs.Mode
 	if tag == "" {
@@ -183,18 +247,12 @@ func (c *TRXClient) Anchor(ctx context.Context, network string, cs dep.Consisten
 			}
 		}
 	case "latest_solid":
-		// 获取最新确认区块（可能需要多次调用或使用特定 API）
-		resp, err := cli.callRPC(ctx2, "wallet/getnowblock", nil)
+		// 固化节点返回已确认（不可回滚）的最新区块
+		num, err := nowBlockNumber(ctx2, cli, true)
 		if err != nil {
 			return dep.AnchorRef{}, err
 		}
-		if blockHeader, ok := resp["block_header"].(map[string]interface{}); ok {
-			if rawData, ok := blockHeader["raw_data"].(map[string]interface{}); ok {
-				if num, ok := rawData["number"].(float64); ok {
-					blockNum = int64(num)
-				}
-			}
-		}
+		blockNum = int64(num)
 	default:
 		// 尝试解析为区块号
 		if num, err := parseBlockNumber(tag); err == nil {
@@ -208,7 +266,7 @@ func (c *TRXClient) Anchor(ctx context.Context, network string, cs dep.Consisten
 		Height:   uint64(blockNum),
 		Tag:      cs.Mode,
 		Network:  network,
-		Provider: cli.name,
+		Provider: *served,
 	}, nil
 }
 
@@ -391,10 +449,6 @@ func (c *TRXClient) getTRC20Balance(ctx context.Context, cli *httpClient, tokenA
 	}, nil
 }
 
-func (c *TRXClient) GetTransaction(ctx context.Context, network string, txHash string) (any, error) {
-	return nil, errors.New("not implemented")
-}
-
 // encodeTRC20BalanceOfParameter 编码 balanceOf(address) 的参数
 func encodeTRC20BalanceOfParameter(address string) (string, error) {
 	// 将 base58 地址转换为 hex 格式（20 字节）
@@ -451,21 +505,6 @@ func MustRegister() {
 
 // ---------------- 工具函数 ----------------
 
-// shortAlias 生成 RPC 提供商的简短别名
-func shortAlias(chain, url string) string {
-	u := url
-	if i := strings.Index(u, "://"); i >= 0 {
-		u = u[i+3:]
-	}
-	if j := strings.Index(u, "/"); j >= 0 {
-		u = u[:j]
-	}
-	if len(u) > 28 {
-		u = u[:28]
-	}
-	return strings.ToLower(chain) + ":" + u
-}
-
 // chunkStrings 将字符串切片分块
 func chunkStrings(ss []string, n int) [][]string {
 	if n <= 0 || len(ss) <= n {
@@ -0,0 +1,68 @@
+package tron
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/reguluswee/walletus/common/chain/dep"
+)
+
+const (
+	// 未提供 from/to 时 TRC-20 transfer 的默认能量（收款方已持有该代币）
+	defaultTRC20Energy int64 = 65_000
+)
+
+// EstimateFee 返回一笔转账在不使用质押资源时需要燃烧的 TRX
+// TRC-20 的能量按 triggerconstantcontract 模拟结果计算，单价取自 getchainparameters
+func (c *TRXClient) EstimateFee(ctx context.Context, network string, q dep.FeeQuery) (*dep.FeeEstimate, error) {
+	cli, err := c.pick(network)
+	if err != nil {
+		return nil, err
+	}
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+
+	var params chainParameters
+	if err := cli.callRPCInto(ctx2, "wallet/getchainparameters", nil, &params); err != nil {
+		return nil, err
+	}
+	out := &dep.FeeEstimate{
+		Symbol:         "TRX",
+		Decimals:       6,
+		BandwidthPrice: params.get("getTransactionFee", defaultBandwidthPrice),
+		EnergyPrice:    params.get("getEnergyFee", defaultEnergyPrice),
+		Bandwidth:      transferTxBandwidth,
+	}
+
+	if q.Token != "" {
+		out.Bandwidth = trc20TxBandwidth
+		out.Energy = defaultTRC20Energy
+		if q.From != "" && q.To != "" {
+			toHex, err := base58AddressToHex(q.To)
+			if err != nil {
+				return nil, dep.ErrInvalidAddress
+			}
+			amount := big.NewInt(0)
+			if q.Amount != "" {
+				if amount, err = parseTRC20Amount(q.Amount); err != nil {
+					return nil, err
+				}
+			}
+			if out.Energy, err = c.simulateTRC20Transfer(ctx2, cli, q.Token, q.From, toHex, amount); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	bandwidthCost := out.Bandwidth * out.BandwidthPrice
+	energyCost := out.Energy * out.EnergyPrice
+	out.BaseFee = big.NewInt(bandwidthCost)
+	out.Tiers = []dep.FeeTier{{
+		Level:       "standard",
+		PriorityFee: big.NewInt(0),
+		Fee:         big.NewInt(bandwidthCost + energyCost),
+	}}
+	return out, nil
+}
+
+var _ dep.FeeEstimator = (*TRXClient)(nil)
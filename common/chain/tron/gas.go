@@ -0,0 +1,79 @@
+package tron
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/reguluswee/walletus/common/chain/dep"
+)
+
+const (
+	// 一笔 TRC-20 transfer 签名后约 345 字节
+	trc20TxBandwidth int64 = 350
+	// 链参数不可用时的兜底：每单位能量 420 sun
+	defaultEnergyPrice int64 = 420
+)
+
+// QuoteTokenTransferGas 计算 TRC-20 转账需要燃烧的 TRX
+// 能量按 triggerconstantcontract 模拟结果扣除账户可用能量后计价，带宽不足时按交易大小计价
+func (c *TRXClient) QuoteTokenTransferGas(ctx context.Context, network, token, from, to string, amount string) (*big.Int, error) {
+	value, err := parseTRC20Amount(amount)
+	if err != nil {
+		return nil, err
+	}
+	toHex, err := base58AddressToHex(to)
+	if err != nil {
+		return nil, dep.ErrInvalidAddress
+	}
+	cli, err := c.pick(network)
+	if err != nil {
+		return nil, err
+	}
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+
+	energy, err := c.simulateTRC20Transfer(ctx2, cli, token, from, toHex, value)
+	if err != nil {
+		return nil, err
+	}
+	res, params, err := c.feeContext(ctx2, cli, from)
+	if err != nil {
+		return nil, err
+	}
+
+	fee := bandwidthFee(res, params, trc20TxBandwidth)
+	if short := energy - (res.EnergyLimit - res.EnergyUsed); short > 0 {
+		fee += short * params.get("getEnergyFee", defaultEnergyPrice)
+	}
+	return big.NewInt(fee), nil
+}
+
+// simulateTRC20Transfer 返回 transfer 调用消耗的能量
+func (c *TRXClient) simulateTRC20Transfer(ctx context.Context, cli *httpClient, token, from string, toHex []byte, value *big.Int) (int64, error) {
+	params := map[string]interface{}{
+		"owner_address":     from,
+		"contract_address":  token,
+		"function_selector": trc20TransferSelector,
+		"parameter":         hex.EncodeToString(encodeTRC20TransferParameter(toHex, value)),
+		"visible":           true,
+	}
+	var resp struct {
+		Result struct {
+			Result  bool   `json:"result"`
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"result"`
+		EnergyUsed int64 `json:"energy_used"`
+	}
+	if err := cli.callRPCInto(ctx, "wallet/triggerconstantcontract", params, &resp); err != nil {
+		return 0, err
+	}
+	if !resp.Result.Result {
+		return 0, fmt.Errorf("trigger constant contract failed: %s %s", resp.Result.Code, decodeTronMessage(resp.Result.Message))
+	}
+	return resp.EnergyUsed, nil
+}
+
+var _ dep.GasQuoter = (*TRXClient)(nil)
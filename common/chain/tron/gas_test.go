@@ -0,0 +1,30 @@
+package tron
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBandwidthFee(t *testing.T) {
+	var params chainParameters
+	if err := json.Unmarshal([]byte(`{"chainParameter":[{"key":"getTransactionFee","value":1000},{"key":"getEnergyFee","value":210}]}`), &params); err != nil {
+		t.Fatal(err)
+	}
+	if v := params.get("getEnergyFee", defaultEnergyPrice); v != 210 {
+		t.Fatalf("energy fee %d", v)
+	}
+	if v := params.get("getCreateAccountFee", 100_000); v != 100_000 {
+		t.Fatalf("default not used: %d", v)
+	}
+
+	// 免费带宽足够时不燃烧 TRX
+	res := &accountResource{FreeNetLimit: 600, FreeNetUsed: 100}
+	if fee := bandwidthFee(res, &params, trc20TxBandwidth); fee != 0 {
+		t.Fatalf("expected free bandwidth, got %d", fee)
+	}
+	// 不足时整笔交易按字节计价，而不是只补差额
+	res = &accountResource{FreeNetLimit: 600, FreeNetUsed: 400}
+	if fee := bandwidthFee(res, &params, trc20TxBandwidth); fee != trc20TxBandwidth*1000 {
+		t.Fatalf("unexpected bandwidth fee %d", fee)
+	}
+}
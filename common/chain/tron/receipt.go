@@ -0,0 +1,183 @@
+package tron
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"strings"
+
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/reguluswee/walletus/common/chain/dep"
+)
+
+// transferLogTopic Transfer(address,address,uint256)，节点返回的 topic 不带 0x 前缀
+var transferLogTopic = hex.EncodeToString(gethcrypto.Keccak256([]byte("Transfer(address,address,uint256)")))
+
+// Receipt 交易回执，费用单位为 sun
+// BlockNumber 为 0 表示交易已被节点接收但尚未打包，此时其余链上字段均为空
+type Receipt struct {
+	TxID        string
+	Success     bool
+	Result      string // 合约调用为 receipt.result（SUCCESS、REVERT、OUT_OF_ENERGY 等），其余交易为 contractRet
+	Message     string // 失败原因
+	BlockNumber uint64
+	BlockTime   int64 // 毫秒
+	EnergyUsage int64 // 含合约部署者承担的部分
+	EnergyFee   int64
+	NetUsage    int64
+	NetFee      int64
+	Fee         int64      // 发送方实际消耗的 TRX
+	Transfers   []Transfer // TRX 转账及 TRC-20 Transfer 事件，只在执行成功时返回
+}
+
+// Pending 交易尚未打包
+func (r *Receipt) Pending() bool {
+	return r.BlockNumber == 0
+}
+
+type rpcTxInfo struct {
+	ID             string `json:"id"`
+	Fee            int64  `json:"fee"`
+	BlockNumber    uint64 `json:"blockNumber"`
+	BlockTimeStamp int64  `json:"blockTimeStamp"`
+	Result         string `json:"result"`
+	ResMessage     string `json:"resMessage"`
+	Receipt        struct {
+		EnergyUsageTotal int64  `json:"energy_usage_total"`
+		EnergyFee        int64  `json:"energy_fee"`
+		NetUsage         int64  `json:"net_usage"`
+		NetFee           int64  `json:"net_fee"`
+		Result           string `json:"result"`
+	} `json:"receipt"`
+	Log []rpcTxLog `json:"log"`
+}
+
+// rpcTxLog 合约事件，address 为不带 41 前缀的 20 字节 hex
+type rpcTxLog struct {
+	Address string   `json:"address"`
+	Topics  []string `json:"topics"`
+	Data    string   `json:"data"`
+}
+
+// GetTransaction 读取交易回执并转换为链无关的结构
+func (c *TRXClient) GetTransaction(ctx context.Context, network string, txHash string, anchor dep.AnchorRef) (*dep.TxReceipt, error) {
+	r, err := c.Transaction(ctx, network, txHash)
+	if err != nil {
+		return nil, err
+	}
+	out := r.TxReceipt(txHash)
+	out.SetAnchor(anchor)
+	return out, nil
+}
+
+// TxReceipt 转换为链无关的回执，r 为 nil 表示交易不存在
+func (r *Receipt) TxReceipt(txHash string) *dep.TxReceipt {
+	out := &dep.TxReceipt{TxHash: txHash, Status: dep.TxStatusNotFound}
+	switch {
+	case r == nil:
+		return out
+	case r.Pending():
+		out.Status = dep.TxStatusPending
+		return out
+	case r.Success:
+		out.Status = dep.TxStatusSuccess
+	default:
+		out.Status = dep.TxStatusFailed
+		out.Error = r.Result
+		if r.Message != "" {
+			out.Error += ": " + r.Message
+		}
+	}
+	out.BlockHeight = r.BlockNumber
+	out.BlockTime = r.BlockTime / 1000
+	out.Fee = big.NewInt(r.Fee)
+	for _, t := range r.Transfers {
+		out.Transfers = append(out.Transfers, dep.TxTransfer{Token: t.Token, From: t.From, To: t.To, Amount: t.Amount})
+	}
+	return out
+}
+
+// Transaction 通过 gettransactionbyid 与 gettransactioninfobyid 读取交易回执
+// 交易不存在时返回 nil；已广播未打包时返回 Pending 的回执
+func (c *TRXClient) Transaction(ctx context.Context, network string, txHash string) (*Receipt, error) {
+	cli, err := c.pick(network)
+	if err != nil {
+		return nil, err
+	}
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+
+	txID := strings.TrimPrefix(txHash, "0x")
+	var tx rpcBlockTx
+	if err := cli.callRPCInto(ctx2, "wallet/gettransactionbyid", map[string]any{"value": txID}, &tx); err != nil {
+		return nil, err
+	}
+	if tx.TxID == "" {
+		return nil, nil
+	}
+	var info rpcTxInfo
+	if err := cli.callRPCInto(ctx2, "wallet/gettransactioninfobyid", map[string]any{"value": txID}, &info); err != nil {
+		return nil, err
+	}
+	return decodeReceipt(&tx, &info), nil
+}
+
+func decodeReceipt(tx *rpcBlockTx, info *rpcTxInfo) *Receipt {
+	r := &Receipt{TxID: tx.TxID}
+	if info.ID == "" {
+		return r
+	}
+	r.BlockNumber = info.BlockNumber
+	r.BlockTime = info.BlockTimeStamp
+	r.EnergyUsage = info.Receipt.EnergyUsageTotal
+	r.EnergyFee = info.Receipt.EnergyFee
+	r.NetUsage = info.Receipt.NetUsage
+	r.NetFee = info.Receipt.NetFee
+	r.Fee = info.Fee
+
+	// 非合约调用的回执没有 result，以交易的 contractRet 为准
+	r.Result = info.Receipt.Result
+	if r.Result == "" && len(tx.Ret) > 0 {
+		r.Result = tx.Ret[0].ContractRet
+	}
+	r.Success = r.Result == "SUCCESS" && info.Result != "FAILED"
+	if !r.Success {
+		r.Message = decodeTronMessage(info.ResMessage)
+		return r
+	}
+
+	if len(tx.RawData.Contract) == 1 && tx.RawData.Contract[0].Type == "TransferContract" {
+		if t, ok := decodeTransfer(tx); ok {
+			r.Transfers = append(r.Transfers, t)
+		}
+	}
+	r.Transfers = append(r.Transfers, decodeTransferLogs(tx.TxID, info.Log)...)
+	return r
+}
+
+// decodeTransferLogs 只接受标准 TRC-20 Transfer（from、to 为 indexed，value 在 data 中）
+func decodeTransferLogs(txID string, logs []rpcTxLog) []Transfer {
+	var out []Transfer
+	for _, l := range logs {
+		if len(l.Topics) != 3 || !strings.EqualFold(l.Topics[0], transferLogTopic) {
+			continue
+		}
+		token, err := hexToBase58Address(l.Address)
+		if err != nil {
+			continue
+		}
+		fromWord, err1 := hex.DecodeString(l.Topics[1])
+		toWord, err2 := hex.DecodeString(l.Topics[2])
+		data, err3 := hex.DecodeString(l.Data)
+		if err1 != nil || err2 != nil || err3 != nil || len(fromWord) != 32 || len(toWord) != 32 || len(data) != 32 {
+			continue
+		}
+		from, err1 := wordToBase58(fromWord)
+		to, err2 := wordToBase58(toWord)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		out = append(out, Transfer{TxID: txID, Token: token, From: from, To: to, Amount: new(big.Int).SetBytes(data)})
+	}
+	return out
+}
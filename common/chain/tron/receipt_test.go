@@ -0,0 +1,51 @@
+package tron
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+)
+
+func TestDecodeReceipt(t *testing.T) {
+	fromWord := "000000000000000000000000" + "11" + hex.EncodeToString(make([]byte, 19))
+	toWord := "000000000000000000000000" + hex.EncodeToString(make([]byte, 19)) + "22"
+	amount := "00000000000000000000000000000000000000000000000000000000000f4240"
+
+	var tx rpcBlockTx
+	if err := json.Unmarshal([]byte(`{"txID":"aa","ret":[{"contractRet":"SUCCESS"}],
+		"raw_data":{"contract":[{"type":"TriggerSmartContract"}]}}`), &tx); err != nil {
+		t.Fatal(err)
+	}
+	var info rpcTxInfo
+	if err := json.Unmarshal([]byte(`{"id":"aa","fee":345000,"blockNumber":100,"blockTimeStamp":1700000000000,
+		"receipt":{"energy_usage_total":64285,"energy_fee":345000,"net_usage":345,"result":"SUCCESS"},
+		"log":[{"address":"a614f803b6fd780986a42c78ec9c7f77e6ded13c",
+			"topics":["`+transferLogTopic+`","`+fromWord+`","`+toWord+`"],"data":"`+amount+`"}]}`), &info); err != nil {
+		t.Fatal(err)
+	}
+
+	r := decodeReceipt(&tx, &info)
+	if !r.Success || r.Pending() || r.BlockNumber != 100 || r.Fee != 345000 || r.EnergyUsage != 64285 || r.NetUsage != 345 {
+		t.Fatalf("unexpected receipt %+v", r)
+	}
+	if len(r.Transfers) != 1 {
+		t.Fatalf("transfers = %d, want 1", len(r.Transfers))
+	}
+	tr := r.Transfers[0]
+	wantTo, _ := hexToBase58Address(toWord[24:])
+	if tr.Token != "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t" || tr.To != wantTo || tr.Amount.Int64() != 1_000_000 {
+		t.Fatalf("unexpected transfer %+v", tr)
+	}
+
+	info.Receipt.Result = "REVERT"
+	info.Result = "FAILED"
+	info.ResMessage = hex.EncodeToString([]byte("REVERT opcode executed"))
+	r = decodeReceipt(&tx, &info)
+	if r.Success || r.Result != "REVERT" || r.Message != "REVERT opcode executed" || len(r.Transfers) != 0 {
+		t.Fatalf("unexpected failed receipt %+v", r)
+	}
+
+	if r = decodeReceipt(&tx, &rpcTxInfo{}); !r.Pending() || r.Success {
+		t.Fatalf("empty info should be pending: %+v", r)
+	}
+}
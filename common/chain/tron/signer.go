@@ -0,0 +1,266 @@
+package tron
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/reguluswee/walletus/common/bip"
+	"github.com/reguluswee/walletus/common/chain/dep"
+)
+
+const (
+	// 默认 fee_limit 50 TRX，足够覆盖无能量时的 USDT 转账
+	defaultFeeLimit int64 = 50_000_000
+
+	trc20TransferSelector = "transfer(address,uint256)"
+)
+
+// unsignedTx 节点返回的待签名交易，字段保持原始 JSON 以便原样广播
+type unsignedTx struct {
+	TxID       string          `json:"txID"`
+	RawData    json.RawMessage `json:"raw_data"`
+	RawDataHex string          `json:"raw_data_hex"`
+	Visible    bool            `json:"visible"`
+}
+
+// signedTx 广播到 wallet/broadcasttransaction 的交易结构
+type signedTx struct {
+	TxID       string          `json:"txID"`
+	RawData    json.RawMessage `json:"raw_data"`
+	RawDataHex string          `json:"raw_data_hex"`
+	Visible    bool            `json:"visible"`
+	Signature  []string        `json:"signature"`
+}
+
+// SignTransferNative 通过 wallet/createtransaction 构建 TransferContract 并签名
+// 返回的 rawTx 为已签名交易的 JSON，可直接提交给 wallet/broadcasttransaction
+func (c *TRXClient) SignTransferNative(ctx context.Context, network string, from, to string, amount string, opts any) ([]byte, string, error) {
+	o, err := dep.ToSignOptions(opts)
+	if err != nil {
+		return nil, "", err
+	}
+	value, err := dep.ParseAmount(amount)
+	if err != nil {
+		return nil, "", err
+	}
+	if !value.IsInt64() {
+		return nil, "", dep.ErrInvalidAmount
+	}
+	fromHex, err := base58AddressToHex(from)
+	if err != nil {
+		return nil, "", dep.ErrInvalidAddress
+	}
+	toHex, err := base58AddressToHex(to)
+	if err != nil {
+		return nil, "", dep.ErrInvalidAddress
+	}
+	priv, err := signingKey(from, o.Key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	cli, err := c.pick(network)
+	if err != nil {
+		return nil, "", err
+	}
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+
+	params := map[string]interface{}{
+		"owner_address": from,
+		"to_address":    to,
+		"amount":        value.Int64(),
+		"visible":       true,
+	}
+	var tx unsignedTx
+	if err := cli.callRPCInto(ctx2, "wallet/createtransaction", params, &tx); err != nil {
+		return nil, "", err
+	}
+
+	// TransferContract: owner_address(1) to_address(2) amount(3)
+	expect := protoBytesField(1, tronAddr(fromHex))
+	expect = append(expect, protoBytesField(2, tronAddr(toHex))...)
+	expect = append(expect, protoVarintField(3, uint64(value.Int64()))...)
+
+	return signUnsignedTx(&tx, expect, priv)
+}
+
+// SignTransferToken 通过 wallet/triggersmartcontract 构建 TRC-20 transfer 调用并签名
+func (c *TRXClient) SignTransferToken(ctx context.Context, network, token, from, to string, amount string, opts any) ([]byte, string, error) {
+	o, err := dep.ToSignOptions(opts)
+	if err != nil {
+		return nil, "", err
+	}
+	value, err := parseTRC20Amount(amount)
+	if err != nil {
+		return nil, "", err
+	}
+	fromHex, err := base58AddressToHex(from)
+	if err != nil {
+		return nil, "", dep.ErrInvalidAddress
+	}
+	toHex, err := base58AddressToHex(to)
+	if err != nil {
+		return nil, "", dep.ErrInvalidAddress
+	}
+	tokenHex, err := base58AddressToHex(token)
+	if err != nil {
+		return nil, "", dep.ErrInvalidAddress
+	}
+	priv, err := signingKey(from, o.Key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	feeLimit := o.FeeLimit
+	if feeLimit <= 0 {
+		feeLimit = defaultFeeLimit
+	}
+
+	cli, err := c.pick(network)
+	if err != nil {
+		return nil, "", err
+	}
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	defer cancel()
+
+	parameter := encodeTRC20TransferParameter(toHex, value)
+	params := map[string]interface{}{
+		"owner_address":     from,
+		"contract_address":  token,
+		"function_selector": trc20TransferSelector,
+		"parameter":         hex.EncodeToString(parameter),
+		"fee_limit":         feeLimit,
+		"call_value":        0,
+		"visible":           true,
+	}
+	var resp struct {
+		Result struct {
+			Result  bool   `json:"result"`
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"result"`
+		Transaction unsignedTx `json:"transaction"`
+	}
+	if err := cli.callRPCInto(ctx2, "wallet/triggersmartcontract", params, &resp); err != nil {
+		return nil, "", err
+	}
+	if !resp.Result.Result {
+		return nil, "", fmt.Errorf("trigger smart contract failed: %s %s", resp.Result.Code, decodeTronMessage(resp.Result.Message))
+	}
+
+	// TriggerSmartContract: owner_address(1) contract_address(2) data(4)
+	data := append(trc20Selector(trc20TransferSelector), parameter...)
+	expect := protoBytesField(1, tronAddr(fromHex))
+	expect = append(expect, protoBytesField(2, tronAddr(tokenHex))...)
+	expect = append(expect, protoBytesField(4, data)...)
+
+	return signUnsignedTx(&resp.Transaction, expect, priv)
+}
+
+// signUnsignedTx 校验节点返回的交易内容并对 raw_data 哈希签名
+// expect 为合约参数的 protobuf 编码片段，用于防止节点篡改收款方或金额
+func signUnsignedTx(tx *unsignedTx, expect []byte, priv *ecdsa.PrivateKey) ([]byte, string, error) {
+	raw, err := hex.DecodeString(tx.RawDataHex)
+	if err != nil || len(raw) == 0 {
+		return nil, "", fmt.Errorf("invalid raw_data_hex from node")
+	}
+	hash := sha256.Sum256(raw)
+	if !strings.EqualFold(hex.EncodeToString(hash[:]), tx.TxID) {
+		return nil, "", fmt.Errorf("txID mismatch with raw_data_hex")
+	}
+	if !bytes.Contains(raw, expect) {
+		return nil, "", fmt.Errorf("node returned unexpected transaction content")
+	}
+
+	sig, err := gethcrypto.Sign(hash[:], priv)
+	if err != nil {
+		return nil, "", fmt.Errorf("sign tx: %w", err)
+	}
+	out, err := json.Marshal(signedTx{
+		TxID:       tx.TxID,
+		RawData:    tx.RawData,
+		RawDataHex: tx.RawDataHex,
+		Visible:    tx.Visible,
+		Signature:  []string{hex.EncodeToString(sig)},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return out, tx.TxID, nil
+}
+
+// signingKey 按派生路径取出私钥，并校验其 base58 地址与 from 一致
+func signingKey(from string, k dep.KeyRef) (*ecdsa.PrivateKey, error) {
+	enc, err := bip.EncMasterFromKeyRef(k)
+	if err != nil {
+		return nil, err
+	}
+	addr, priv, err := bip.AddressAndPrivFromPath(enc, k.Path, "TRON")
+	if err != nil {
+		return nil, err
+	}
+	if addr != from {
+		return nil, dep.ErrKeyMismatch
+	}
+	return priv, nil
+}
+
+// parseTRC20Amount 解析代币数量，超出 uint256 时返回 ErrInvalidAmount
+func parseTRC20Amount(amount string) (*big.Int, error) {
+	value, err := dep.ParseAmount(amount)
+	if err != nil {
+		return nil, err
+	}
+	if value.BitLen() > 256 {
+		return nil, dep.ErrInvalidAmount
+	}
+	return value, nil
+}
+
+// encodeTRC20TransferParameter 编码 transfer(address,uint256) 的参数（不含选择器）
+func encodeTRC20TransferParameter(to []byte, amount *big.Int) []byte {
+	param := make([]byte, 64)
+	copy(param[32-len(to):32], to)
+	amount.FillBytes(param[32:])
+	return param
+}
+
+func trc20Selector(sig string) []byte {
+	return gethcrypto.Keccak256([]byte(sig))[:4]
+}
+
+// tronAddr 20 字节地址加上 0x41 前缀
+func tronAddr(addr20 []byte) []byte {
+	return append([]byte{0x41}, addr20...)
+}
+
+func protoBytesField(field int, b []byte) []byte {
+	out := binary.AppendUvarint(nil, uint64(field<<3|2))
+	out = binary.AppendUvarint(out, uint64(len(b)))
+	return append(out, b...)
+}
+
+func protoVarintField(field int, v uint64) []byte {
+	out := binary.AppendUvarint(nil, uint64(field<<3))
+	return binary.AppendUvarint(out, v)
+}
+
+// decodeTronMessage 节点错误信息通常为 hex 编码
+func decodeTronMessage(msg string) string {
+	if b, err := hex.DecodeString(msg); err == nil {
+		return string(b)
+	}
+	return msg
+}
+
+var _ dep.Signer = (*TRXClient)(nil)
@@ -0,0 +1,89 @@
+package tron
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/reguluswee/walletus/common/chain/dep"
+)
+
+func TestSignUnsignedTx(t *testing.T) {
+	priv, err := gethcrypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner, _ := base58AddressToHex("TB1LbnUG14S6i2tWgf1pRtwXt6WHTtcSVL")
+	to, _ := base58AddressToHex("TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t")
+
+	contract := protoBytesField(1, tronAddr(owner))
+	contract = append(contract, protoBytesField(2, tronAddr(to))...)
+	contract = append(contract, protoVarintField(3, 1_000_000)...)
+	// 模拟 raw_data：ref_block_bytes + 合约参数
+	raw := append(protoBytesField(1, []byte{0x12, 0x34}), contract...)
+	hash := sha256.Sum256(raw)
+
+	tx := &unsignedTx{
+		TxID:       hex.EncodeToString(hash[:]),
+		RawData:    json.RawMessage(`{"expiration":1700000000000}`),
+		RawDataHex: hex.EncodeToString(raw),
+		Visible:    true,
+	}
+	out, txID, err := signUnsignedTx(tx, contract, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txID != tx.TxID {
+		t.Fatalf("txID mismatch: %s", txID)
+	}
+	var signed signedTx
+	if err := json.Unmarshal(out, &signed); err != nil {
+		t.Fatal(err)
+	}
+	sig, _ := hex.DecodeString(signed.Signature[0])
+	pub, err := gethcrypto.SigToPub(hash[:], sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gethcrypto.PubkeyToAddress(*pub) != gethcrypto.PubkeyToAddress(priv.PublicKey) {
+		t.Fatal("recovered signer mismatch")
+	}
+
+	// 节点篡改收款方
+	other := protoBytesField(2, tronAddr(owner))
+	if _, _, err := signUnsignedTx(tx, other, priv); err == nil {
+		t.Fatal("expected tampered transaction to be rejected")
+	}
+}
+
+func TestEncodeTRC20TransferParameter(t *testing.T) {
+	to, _ := base58AddressToHex("TB1LbnUG14S6i2tWgf1pRtwXt6WHTtcSVL")
+	param := encodeTRC20TransferParameter(to, big.NewInt(1_000_000))
+	if len(param) != 64 {
+		t.Fatalf("unexpected length %d", len(param))
+	}
+	if hex.EncodeToString(param[12:32]) != hex.EncodeToString(to) {
+		t.Fatal("address not left padded")
+	}
+	if new(big.Int).SetBytes(param[32:]).Int64() != 1_000_000 {
+		t.Fatal("amount mismatch")
+	}
+	if hex.EncodeToString(trc20Selector(trc20TransferSelector)) != "a9059cbb" {
+		t.Fatal("selector mismatch")
+	}
+}
+
+func TestParseTRC20Amount(t *testing.T) {
+	maxUint256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	if v, err := parseTRC20Amount(maxUint256.String()); err != nil || v.Cmp(maxUint256) != 0 {
+		t.Fatalf("max uint256 rejected: %v", err)
+	}
+	tooLarge := new(big.Int).Lsh(big.NewInt(1), 256).String()
+	if _, err := parseTRC20Amount(tooLarge); !errors.Is(err, dep.ErrInvalidAmount) {
+		t.Fatalf("expected ErrInvalidAmount, got %v", err)
+	}
+}
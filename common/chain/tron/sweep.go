@@ -0,0 +1,139 @@
+package tron
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/reguluswee/walletus/common/chain/dep"
+)
+
+const (
+	// 一笔 TransferContract 签名后约 270 字节，按 300 字节预留带宽
+	transferTxBandwidth int64 = 300
+	// 链参数不可用时的兜底：每字节带宽 1000 sun
+	defaultBandwidthPrice int64 = 1000
+)
+
+// BuildAndSignSweep 将 from 上的全部余额归集到 dest
+// TRX 归集按带宽（免费带宽不足时燃烧 TRX）及目标账户激活费预留手续费
+// TRC-20 归集转出全部代币余额，能量费用由 from 上的 TRX 支付
+func (c *TRXClient) BuildAndSignSweep(ctx context.Context, network string, from string, dest string, opts any) ([]byte, string, string, error) {
+	o, err := dep.ToSweepOptions(opts)
+	if err != nil {
+		return nil, "", "", err
+	}
+	cli, err := c.pick(network)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	if o.Token != "" {
+		ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+		tb, err := c.getTRC20Balance(ctx2, cli, o.Token, from)
+		cancel()
+		if err != nil {
+			return nil, "", "", err
+		}
+		if tb.Amount == nil || tb.Amount.Sign() <= 0 {
+			return nil, "", "", dep.ErrNothingToSweep
+		}
+		raw, hash, err := c.SignTransferToken(ctx, network, o.Token, from, dest, tb.Amount.String(), &o.SignOptions)
+		return raw, hash, tb.Amount.String(), err
+	}
+
+	ctx2, cancel := context.WithTimeout(ctx, c.reqTimeout)
+	nb, err := c.getAccountBalance(ctx2, cli, from)
+	if err != nil {
+		cancel()
+		return nil, "", "", err
+	}
+	fee, err := c.nativeTransferFee(ctx2, cli, from, dest)
+	cancel()
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	amount := new(big.Int).Sub(nb.Amount, big.NewInt(fee))
+	if amount.Sign() <= 0 {
+		return nil, "", "", dep.ErrNothingToSweep
+	}
+	raw, hash, err := c.SignTransferNative(ctx, network, from, dest, amount.String(), &o.SignOptions)
+	return raw, hash, amount.String(), err
+}
+
+type accountResource struct {
+	FreeNetLimit int64 `json:"freeNetLimit"`
+	FreeNetUsed  int64 `json:"freeNetUsed"`
+	NetLimit     int64 `json:"NetLimit"`
+	NetUsed      int64 `json:"NetUsed"`
+	EnergyLimit  int64 `json:"EnergyLimit"`
+	EnergyUsed   int64 `json:"EnergyUsed"`
+}
+
+type chainParameters struct {
+	ChainParameter []struct {
+		Key   string `json:"key"`
+		Value int64  `json:"value"`
+	} `json:"chainParameter"`
+}
+
+func (p *chainParameters) get(key string, def int64) int64 {
+	for _, kv := range p.ChainParameter {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return def
+}
+
+// nativeTransferFee 估算一笔 TRX 转账需要燃烧的 sun
+func (c *TRXClient) nativeTransferFee(ctx context.Context, cli *httpClient, from, to string) (int64, error) {
+	res, params, err := c.feeContext(ctx, cli, from)
+	if err != nil {
+		return 0, err
+	}
+	fee := bandwidthFee(res, params, transferTxBandwidth)
+
+	activated, err := c.accountActivated(ctx, cli, to)
+	if err != nil {
+		return 0, err
+	}
+	if !activated {
+		fee += params.get("getCreateAccountFee", 100_000) + params.get("getCreateNewAccountFeeInSystemContract", 1_000_000)
+	}
+	return fee, nil
+}
+
+func (c *TRXClient) feeContext(ctx context.Context, cli *httpClient, from string) (*accountResource, *chainParameters, error) {
+	var res accountResource
+	if err := cli.callRPCInto(ctx, "wallet/getaccountresource", map[string]interface{}{"address": from, "visible": true}, &res); err != nil {
+		return nil, nil, err
+	}
+	var params chainParameters
+	if err := cli.callRPCInto(ctx, "wallet/getchainparameters", nil, &params); err != nil {
+		return nil, nil, err
+	}
+	return &res, &params, nil
+}
+
+// bandwidthFee 免费带宽与质押带宽不足以覆盖交易大小时，整笔按字节燃烧 TRX
+func bandwidthFee(res *accountResource, params *chainParameters, size int64) int64 {
+	available := (res.FreeNetLimit - res.FreeNetUsed) + (res.NetLimit - res.NetUsed)
+	if available >= size {
+		return 0
+	}
+	return size * params.get("getTransactionFee", defaultBandwidthPrice)
+}
+
+// accountActivated 未激活账户 wallet/getaccount 返回空对象
+func (c *TRXClient) accountActivated(ctx context.Context, cli *httpClient, addr string) (bool, error) {
+	var acc struct {
+		Address string `json:"address"`
+	}
+	if err := cli.callRPCInto(ctx, "wallet/getaccount", map[string]interface{}{"address": addr, "visible": true}, &acc); err != nil {
+		return false, err
+	}
+	return acc.Address != "", nil
+}
+
+var _ dep.Sweeper = (*TRXClient)(nil)
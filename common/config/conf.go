@@ -36,6 +36,12 @@ type Config struct {
 	Http        HttpConfig        `yaml:"http"`
 	ProxyEnable bool              `yaml:"proxyEnable"`
 	IndexerRoot IndexerRootConfig `yaml:"indexerRoot"`
+	Sweep       SweepConfig       `yaml:"sweep"`
+}
+
+// SweepConfig 自动归集配置，Interval 为执行间隔（秒），0 表示不自动执行
+type SweepConfig struct {
+	Interval int `yaml:"interval"`
 }
 
 // DatabaseConfig holds the database connection parameters.
@@ -52,21 +58,33 @@ type DatabaseConfig struct {
 
 // ChainConfig holds the Solana chain RPC endpoints.
 type ChainConfig struct {
-	Name         string   `yaml:"name"`
-	WsRpc        string   `yaml:"wsRpc"`
-	QueryRpc     []string `yaml:"queryRpc"`
-	SlotParallel int      `yaml:"slotParallel"`
-	TxDetal      int      `yaml:"txDetal"`
-	RangeRound   int      `yaml:"rangeRound"`
+	Name         string          `yaml:"name"`
+	WsRpc        string          `yaml:"wsRpc"`
+	QueryRpc     []string        `yaml:"queryRpc"`
+	SlotParallel int             `yaml:"slotParallel"`
+	TxDetal      int             `yaml:"txDetal"`
+	RangeRound   int             `yaml:"rangeRound"`
+	Tokens       []string        `yaml:"tokens"`
+	Consistency  string          `yaml:"consistency"`
+	Confirms     []ConfirmConfig `yaml:"confirms"`
 	Rpcs         []RpcMapper
 	RpcMap       map[string]int
 }
 
+// ConfirmConfig 入账确认要求，Token 为空的一项作为原生币及未单独配置代币的默认值
+// Finality 为 finalized 时等待链上最终确认（EVM finalized 区块、Solana finalized commitment、TRON 固化块），不再按 Blocks 计数
+type ConfirmConfig struct {
+	Token    string `yaml:"token"`
+	Blocks   int    `yaml:"blocks"`
+	Finality string `yaml:"finality"`
+}
+
 // LogConfig holds the logging directory and file name.
 type LogConfig struct {
 	Path string `yaml:"path"`
 }
 
+// RpcMapper queryRpc 中的一项，格式为 url||quote；Quote 为该提供商的每秒请求上限，省略或为 0 表示不限速
 type RpcMapper struct {
 	Rpc   string
 	Quote int
@@ -139,6 +157,28 @@ func (t *ChainConfig) GetTxDelay() int {
 	return 0
 }
 
+// GetRangeRound 每轮扫描的最大区块数
+func (t *ChainConfig) GetRangeRound() int {
+	if t.RangeRound > 0 {
+		return t.RangeRound
+	}
+	return 1
+}
+
+// GetConfirm 代币的确认要求，未配置时使用链默认值，默认 1 个确认
+func (t *ChainConfig) GetConfirm(token string) ConfirmConfig {
+	def := ConfirmConfig{Blocks: 1}
+	for _, c := range t.Confirms {
+		if c.Token == token {
+			return c
+		}
+		if c.Token == "" {
+			def = c
+		}
+	}
+	return def
+}
+
 var systemConfig = &Config{}
 
 func GetConfig() Config {
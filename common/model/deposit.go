@@ -0,0 +1,74 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// 扫描器发现的入账
+	DepositStatusSeen = "seen"
+	// 已进入后续区块，确认数未达到要求
+	DepositStatusConfirming = "confirming"
+	// 确认数达到要求或已最终确认，租户可以入账
+	DepositStatusConfirmed = "confirmed"
+	// 所在区块因链重组被丢弃
+	DepositStatusReverted = "reverted"
+)
+
+// NativeLogIndex 原生币入账没有日志序号，用 -1 与同一交易中的代币日志区分
+const NativeLogIndex = -1
+
+// TenantDeposit 扫描到的租户地址入账，(chain, tx_hash, log_index) 唯一，重复扫描不会重复记录
+// 状态依次为 seen → confirming → confirmed，只有 confirmed 的入账对租户是最终的
+type TenantDeposit struct {
+	ID              uint64          `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID        uint64          `gorm:"column:tenant_id;not null" json:"tenant_id"`
+	TenantAddressID uint64          `gorm:"column:tenant_address_id;not null" json:"tenant_address_id"`
+	Chain           string          `gorm:"column:chain;type:varchar(50);not null" json:"chain"`
+	Token           string          `gorm:"column:token;type:varchar(255);not null" json:"token"`
+	FromAddress     string          `gorm:"column:from_address;type:varchar(255);not null" json:"from_address"`
+	ToAddress       string          `gorm:"column:to_address;type:varchar(255);not null" json:"to_address"`
+	Amount          decimal.Decimal `gorm:"column:amount;type:decimal(65,0);not null" json:"amount"`
+	TxHash          string          `gorm:"column:tx_hash;type:varchar(128);not null" json:"tx_hash"`
+	LogIndex        int             `gorm:"column:log_index;not null" json:"log_index"`
+	BlockNumber     uint64          `gorm:"column:block_number;not null" json:"block_number"`
+	BlockHash       string          `gorm:"column:block_hash;type:varchar(128);not null" json:"block_hash"`
+	Status          string          `gorm:"column:status;type:varchar(20);not null" json:"status"`
+	Confirmations   int             `gorm:"column:confirmations;not null" json:"confirmations"`
+	Required        int             `gorm:"column:required;not null" json:"required"` // 要求的确认数，0 表示等待最终确认
+	ConfirmTime     *time.Time      `gorm:"column:confirm_time" json:"confirm_time"`
+	AddTime         time.Time       `gorm:"column:add_time" json:"add_time"`
+	UpdateTime      time.Time       `gorm:"column:update_time" json:"update_time"`
+}
+
+func (TenantDeposit) TableName() string {
+	return "tenant_deposit"
+}
+
+// ScanCheckpoint 扫描进度，Height 为已完整扫描的最高区块
+type ScanCheckpoint struct {
+	Chain      string    `gorm:"column:chain;type:varchar(50);primaryKey" json:"chain"`
+	Kind       string    `gorm:"column:kind;type:varchar(20);primaryKey" json:"kind"`
+	Height     uint64    `gorm:"column:height;not null" json:"height"`
+	BlockHash  string    `gorm:"column:block_hash;type:varchar(128);not null" json:"block_hash"`
+	UpdateTime time.Time `gorm:"column:update_time" json:"update_time"`
+}
+
+func (ScanCheckpoint) TableName() string {
+	return "scan_checkpoint"
+}
+
+// ScanBlock 最近扫描过的区块，用于链重组检测，只保留最近一段窗口
+type ScanBlock struct {
+	Chain      string    `gorm:"column:chain;type:varchar(50);primaryKey" json:"chain"`
+	Height     uint64    `gorm:"column:height;primaryKey" json:"height"`
+	Hash       string    `gorm:"column:hash;type:varchar(128);not null" json:"hash"`
+	ParentHash string    `gorm:"column:parent_hash;type:varchar(128);not null" json:"parent_hash"`
+	AddTime    time.Time `gorm:"column:add_time" json:"add_time"`
+}
+
+func (ScanBlock) TableName() string {
+	return "scan_block"
+}
@@ -0,0 +1,67 @@
+package model
+
+import "time"
+
+// 租户事件类型
+const (
+	EventDepositSeen       = "deposit.seen"
+	EventDepositConfirmed  = "deposit.confirmed"
+	EventDepositReverted   = "deposit.reverted"
+	EventWithdrawBroadcast = "withdrawal.broadcast"
+	EventWithdrawConfirmed = "withdrawal.confirmed"
+	EventWithdrawFailed    = "withdrawal.failed"
+	// 测试回调地址，只投递一次，不写入事件表
+	EventPing = "webhook.ping"
+)
+
+// 事件投递状态
+const (
+	EventStatusPending   = "pending"
+	EventStatusDelivered = "delivered"
+	// 重试次数用尽
+	EventStatusFailed = "failed"
+	// 租户未设置回调地址
+	EventStatusSkipped = "skipped"
+)
+
+// TenantEvent 需要通知租户的事件，Payload 为事件对象的 JSON
+// 投递失败时按指数退避重试，NextTime 为下一次投递时间
+type TenantEvent struct {
+	ID          uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID    uint64     `gorm:"column:tenant_id;not null" json:"tenant_id"`
+	EventType   string     `gorm:"column:event_type;type:varchar(50);not null" json:"event_type"`
+	RefID       uint64     `gorm:"column:ref_id;not null" json:"ref_id"`
+	Payload     string     `gorm:"column:payload;type:text;not null" json:"payload"`
+	Status      string     `gorm:"column:status;type:varchar(20);not null" json:"status"`
+	Attempts    int        `gorm:"column:attempts;not null" json:"attempts"`
+	NextTime    time.Time  `gorm:"column:next_time" json:"next_time"`
+	DeliverTime *time.Time `gorm:"column:deliver_time" json:"deliver_time"`
+	AddTime     time.Time  `gorm:"column:add_time" json:"add_time"`
+}
+
+func (TenantEvent) TableName() string {
+	return "tenant_event"
+}
+
+// TenantEventDelivery 每次投递的记录，StatusCode 为 0 表示请求未得到响应
+type TenantEventDelivery struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	EventID    uint64    `gorm:"column:event_id;not null" json:"event_id"`
+	TenantID   uint64    `gorm:"column:tenant_id;not null" json:"tenant_id"`
+	Attempt    int       `gorm:"column:attempt;not null" json:"attempt"`
+	URL        string    `gorm:"column:url;type:varchar(255);not null" json:"url"`
+	StatusCode int       `gorm:"column:status_code;not null" json:"status_code"`
+	Response   string    `gorm:"column:response;type:varchar(1024);not null" json:"response"`
+	ErrMsg     string    `gorm:"column:err_msg;type:varchar(1024);not null" json:"err_msg"`
+	Duration   int64     `gorm:"column:duration;not null" json:"duration"` // 毫秒
+	AddTime    time.Time `gorm:"column:add_time" json:"add_time"`
+}
+
+func (TenantEventDelivery) TableName() string {
+	return "tenant_event_delivery"
+}
+
+// Succeeded 租户回调返回 2xx
+func (d *TenantEventDelivery) Succeeded() bool {
+	return d.StatusCode >= 200 && d.StatusCode < 300
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RiskRuleAnyToken 链级默认规则的 Token，适用于该链上没有单独规则的币种
+const RiskRuleAnyToken = "*"
+
+// TenantRiskRule 租户在某条链上某个币种（Token 为空表示原生币，为 RiskRuleAnyToken 表示链级默认）的提现风控规则
+// 金额均为最小单位，取 0 表示不限制；链上任一规则开启 StrictAllowlist 时该链所有币种都只能提现到白名单地址
+type TenantRiskRule struct {
+	ID              uint64          `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID        uint64          `gorm:"column:tenant_id;not null" json:"tenant_id"`
+	Chain           string          `gorm:"column:chain;type:varchar(50);not null" json:"chain"`
+	Token           string          `gorm:"column:token;type:varchar(255);not null" json:"token"`
+	SingleMax       decimal.Decimal `gorm:"column:single_max;type:decimal(65,0);not null" json:"single_max"`
+	DailyMax        decimal.Decimal `gorm:"column:daily_max;type:decimal(65,0);not null" json:"daily_max"`
+	DailyCount      int             `gorm:"column:daily_count;not null" json:"daily_count"`
+	PerDestDailyMax decimal.Decimal `gorm:"column:per_dest_daily_max;type:decimal(65,0);not null" json:"per_dest_daily_max"`
+	StrictAllowlist uint8           `gorm:"column:strict_allowlist;type:tinyint(1);not null" json:"strict_allowlist"`
+	AddTime         time.Time       `gorm:"column:add_time" json:"add_time"`
+	UpdateTime      time.Time       `gorm:"column:update_time" json:"update_time"`
+	Flag            uint8           `gorm:"column:flag" json:"flag"`
+}
+
+func (TenantRiskRule) TableName() string {
+	return "tenant_risk_rule"
+}
+
+// TenantAllowlist 提现目标地址白名单，规则开启 StrictAllowlist 时只允许提现到名单内地址
+type TenantAllowlist struct {
+	ID       uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID uint64    `gorm:"column:tenant_id;not null" json:"tenant_id"`
+	Chain    string    `gorm:"column:chain;type:varchar(50);not null" json:"chain"`
+	Address  string    `gorm:"column:address;type:varchar(255);not null" json:"address"`
+	Label    string    `gorm:"column:label;type:varchar(255);not null" json:"label"`
+	AddTime  time.Time `gorm:"column:add_time" json:"add_time"`
+	Flag     uint8     `gorm:"column:flag" json:"flag"`
+}
+
+func (TenantAllowlist) TableName() string {
+	return "tenant_allowlist"
+}
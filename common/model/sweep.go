@@ -0,0 +1,96 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	SweepStatusCreated   = "created"
+	SweepStatusBroadcast = "broadcast"
+	SweepStatusSkipped   = "skipped"
+	SweepStatusFailed    = "failed"
+
+	TopupStatusCreated   = "created"
+	TopupStatusBroadcast = "broadcast"
+	TopupStatusConfirmed = "confirmed"
+	TopupStatusFailed    = "failed"
+)
+
+// TenantCollect 租户在某条链上的归集地址及原生币归集阈值（最小单位）
+// FeePayer 为代币归集补充手续费的租户地址，为空时不补充
+type TenantCollect struct {
+	ID             uint64          `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID       uint64          `gorm:"column:tenant_id;not null" json:"tenant_id"`
+	Chain          string          `gorm:"column:chain;type:varchar(50);not null" json:"chain"`
+	CollectAddress string          `gorm:"column:collect_address;type:varchar(255);not null" json:"collect_address"`
+	NativeMin      decimal.Decimal `gorm:"column:native_min;type:decimal(65,0);not null" json:"native_min"`
+	FeePayer       string          `gorm:"column:fee_payer;type:varchar(255);not null" json:"fee_payer"`
+	AddTime        time.Time       `gorm:"column:add_time" json:"add_time"`
+	UpdateTime     time.Time       `gorm:"column:update_time" json:"update_time"`
+	Flag           uint8           `gorm:"column:flag" json:"flag"`
+}
+
+func (TenantCollect) TableName() string {
+	return "tenant_collect"
+}
+
+// TenantCollectToken 需要归集的代币及其归集阈值（最小单位）
+type TenantCollectToken struct {
+	ID         uint64          `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID   uint64          `gorm:"column:tenant_id;not null" json:"tenant_id"`
+	Chain      string          `gorm:"column:chain;type:varchar(50);not null" json:"chain"`
+	Token      string          `gorm:"column:token;type:varchar(255);not null" json:"token"`
+	MinAmount  decimal.Decimal `gorm:"column:min_amount;type:decimal(65,0);not null" json:"min_amount"`
+	AddTime    time.Time       `gorm:"column:add_time" json:"add_time"`
+	UpdateTime time.Time       `gorm:"column:update_time" json:"update_time"`
+	Flag       uint8           `gorm:"column:flag" json:"flag"`
+}
+
+func (TenantCollectToken) TableName() string {
+	return "tenant_collect_token"
+}
+
+// TenantSweep 单个地址的一次归集任务，Token 为空表示原生币
+type TenantSweep struct {
+	ID              uint64          `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID        uint64          `gorm:"column:tenant_id;not null" json:"tenant_id"`
+	Chain           string          `gorm:"column:chain;type:varchar(50);not null" json:"chain"`
+	Token           string          `gorm:"column:token;type:varchar(255);not null" json:"token"`
+	TenantAddressID uint64          `gorm:"column:tenant_address_id;not null" json:"tenant_address_id"`
+	FromAddress     string          `gorm:"column:from_address;type:varchar(255);not null" json:"from_address"`
+	ToAddress       string          `gorm:"column:to_address;type:varchar(255);not null" json:"to_address"`
+	Amount          decimal.Decimal `gorm:"column:amount;type:decimal(65,0);not null" json:"amount"`
+	Status          string          `gorm:"column:status;type:varchar(50);not null" json:"status"`
+	TxHash          string          `gorm:"column:tx_hash;type:varchar(255);not null" json:"tx_hash"`
+	ErrMsg          string          `gorm:"column:err_msg;type:varchar(1024);not null" json:"err_msg"`
+	AddTime         time.Time       `gorm:"column:add_time" json:"add_time"`
+	UpdateTime      time.Time       `gorm:"column:update_time" json:"update_time"`
+}
+
+func (TenantSweep) TableName() string {
+	return "tenant_sweep"
+}
+
+// TenantGasTopup 代币归集前由手续费地址向充值地址补充的原生币，用于按租户核对 gas 支出
+type TenantGasTopup struct {
+	ID              uint64          `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID        uint64          `gorm:"column:tenant_id;not null" json:"tenant_id"`
+	Chain           string          `gorm:"column:chain;type:varchar(50);not null" json:"chain"`
+	Token           string          `gorm:"column:token;type:varchar(255);not null" json:"token"`
+	TenantAddressID uint64          `gorm:"column:tenant_address_id;not null" json:"tenant_address_id"`
+	FeePayer        string          `gorm:"column:fee_payer;type:varchar(255);not null" json:"fee_payer"`
+	ToAddress       string          `gorm:"column:to_address;type:varchar(255);not null" json:"to_address"`
+	Required        decimal.Decimal `gorm:"column:required;type:decimal(65,0);not null" json:"required"`
+	Amount          decimal.Decimal `gorm:"column:amount;type:decimal(65,0);not null" json:"amount"`
+	Status          string          `gorm:"column:status;type:varchar(50);not null" json:"status"`
+	TxHash          string          `gorm:"column:tx_hash;type:varchar(255);not null" json:"tx_hash"`
+	ErrMsg          string          `gorm:"column:err_msg;type:varchar(1024);not null" json:"err_msg"`
+	AddTime         time.Time       `gorm:"column:add_time" json:"add_time"`
+	UpdateTime      time.Time       `gorm:"column:update_time" json:"update_time"`
+}
+
+func (TenantGasTopup) TableName() string {
+	return "tenant_gas_topup"
+}
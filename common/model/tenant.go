@@ -15,6 +15,7 @@ type Tenant struct {
 	Callback      string    `gorm:"column:call_back;type:varchar(255);not null" json:"call_back"`
 	Flag          uint8     `gorm:"column:flag;type:tinyint(1);not null" json:"flag"`
 	APIID         uint64    `gorm:"column:api_id;type:int(11);not null" json:"api_id"`
+	WebhookSalt   string    `gorm:"column:webhook_salt;type:varchar(64);not null" json:"-"` // 回调签名密钥的派生盐，轮换密钥时更换
 }
 
 func (Tenant) TableName() string {
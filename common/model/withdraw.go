@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	WithdrawStatusCreated   = "created"
+	WithdrawStatusSigned    = "signed"
+	WithdrawStatusBroadcast = "broadcast"
+	WithdrawStatusConfirmed = "confirmed"
+	WithdrawStatusFailed    = "failed"
+	// 触发风控规则，等待人工审核
+	WithdrawStatusReview   = "review"
+	WithdrawStatusRejected = "rejected"
+)
+
+// TenantWithdraw 租户提现订单，(tenant_id, request_id) 唯一，保证重试幂等
+// Token 为空表示原生币，Amount 为最小单位
+type TenantWithdraw struct {
+	ID              uint64          `gorm:"primaryKey;autoIncrement" json:"id"`
+	TenantID        uint64          `gorm:"column:tenant_id;not null" json:"tenant_id"`
+	RequestID       string          `gorm:"column:request_id;type:varchar(100);not null" json:"request_id"`
+	Chain           string          `gorm:"column:chain;type:varchar(50);not null" json:"chain"`
+	Token           string          `gorm:"column:token;type:varchar(255);not null" json:"token"`
+	TenantAddressID uint64          `gorm:"column:tenant_address_id;not null" json:"tenant_address_id"`
+	FromAddress     string          `gorm:"column:from_address;type:varchar(255);not null" json:"from_address"`
+	ToAddress       string          `gorm:"column:to_address;type:varchar(255);not null" json:"to_address"`
+	Amount          decimal.Decimal `gorm:"column:amount;type:decimal(65,0);not null" json:"amount"`
+	Status          string          `gorm:"column:status;type:varchar(50);not null" json:"status"`
+	TxHash          string          `gorm:"column:tx_hash;type:varchar(255);not null" json:"tx_hash"`
+	RawTx           string          `gorm:"column:raw_tx;type:text" json:"-"`
+	ErrMsg          string          `gorm:"column:err_msg;type:varchar(1024);not null" json:"err_msg"`
+	ReviewReason    string          `gorm:"column:review_reason;type:varchar(1024);not null" json:"review_reason"`
+	ReviewerID      uint64          `gorm:"column:reviewer_id;not null" json:"reviewer_id"`
+	ReviewRemark    string          `gorm:"column:review_remark;type:varchar(1024);not null" json:"review_remark"`
+	ReviewTime      *time.Time      `gorm:"column:review_time" json:"review_time"`
+	AddTime         time.Time       `gorm:"column:add_time" json:"add_time"`
+	UpdateTime      time.Time       `gorm:"column:update_time" json:"update_time"`
+	ConfirmTime     *time.Time      `gorm:"column:confirm_time" json:"confirm_time"`
+}
+
+func (TenantWithdraw) TableName() string {
+	return "tenant_withdraw"
+}
@@ -93,7 +93,11 @@ func (q *SlotQueue) Consumer(size int, handler processSlotHandler) {
 			items, err := q.BatchDequeue(size)
 			if err != nil {
 				fmt.Printf("Get Consume Batch: %d, %v", size, err)
-				continue
+				break
+			}
+			// 队列已空，等待下一次入队通知
+			if len(items) == 0 {
+				break
 			}
 			var wg sync.WaitGroup
 			for _, item := range items {
@@ -0,0 +1,236 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/reguluswee/walletus/common/log"
+	"github.com/reguluswee/walletus/common/model"
+	"github.com/reguluswee/walletus/common/system"
+	"gorm.io/gorm"
+)
+
+const (
+	// RichQueue 的去重表是全局的，事件 ID 加前缀避免与其他队列冲突
+	queueKeyPrefix = "webhook:"
+
+	maxAttempts    = 12
+	baseDelay      = 10 * time.Second
+	maxDelay       = time.Hour
+	loadInterval   = 5 * time.Second
+	loadBatch      = 200
+	deliverTimeout = 10 * time.Second
+	parallel       = 8
+	maxResponseLen = 1024
+)
+
+// envelope 回调请求体，Data 为事件对象
+type envelope struct {
+	ID        uint64          `json:"id"`
+	Type      string          `json:"type"`
+	TenantID  uint64          `json:"tenant_id"`
+	CreatedAt int64           `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Dispatcher 将 pending 事件 POST 到租户的回调地址
+// 非 2xx 或请求失败时按指数退避经 EnqueueWithDelay 重试，每次投递都记录到 tenant_event_delivery
+type Dispatcher struct {
+	db     *gorm.DB
+	queue  *system.RichQueue[string]
+	client *http.Client
+}
+
+func NewDispatcher(db *gorm.DB) *Dispatcher {
+	return &Dispatcher{
+		db:     db,
+		queue:  system.NewRichQueue[string](),
+		client: &http.Client{Timeout: deliverTimeout},
+	}
+}
+
+// StartDispatcher 启动事件投递，直到 ctx 取消
+func StartDispatcher(ctx context.Context) {
+	NewDispatcher(system.GetDb()).Run(ctx)
+}
+
+// Run 定期加载到期的 pending 事件入队；进程重启后未完成的重试也由此恢复
+func (d *Dispatcher) Run(ctx context.Context) {
+	log.Info("[webhook] dispatcher starting...")
+	go d.queue.ConsumerWithContext(ctx, parallel, func(key string, _ *sync.WaitGroup) {
+		d.handle(ctx, key)
+	})
+
+	ticker := time.NewTicker(loadInterval)
+	defer ticker.Stop()
+	for {
+		d.load()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Dispatcher) load() {
+	var ids []uint64
+	err := d.db.Model(&model.TenantEvent{}).
+		Where("status = ? and next_time <= ?", model.EventStatusPending, time.Now()).
+		Order("id").Limit(loadBatch).Pluck("id", &ids).Error
+	if err != nil {
+		log.Error("[webhook] load pending events failed: ", err)
+		return
+	}
+	for _, id := range ids {
+		d.queue.Enqueue(queueKey(id))
+	}
+}
+
+func (d *Dispatcher) handle(ctx context.Context, key string) {
+	id, err := strconv.ParseUint(strings.TrimPrefix(key, queueKeyPrefix), 10, 64)
+	if err != nil {
+		return
+	}
+	var ev model.TenantEvent
+	d.db.Where("id = ?", id).Limit(1).Find(&ev)
+	if ev.ID == 0 || ev.Status != model.EventStatusPending || ev.NextTime.After(time.Now().Add(time.Second)) {
+		return
+	}
+
+	var tenant model.Tenant
+	d.db.Where("id = ?", ev.TenantID).Limit(1).Find(&tenant)
+	if tenant.Callback == "" {
+		d.db.Model(&ev).Where("status = ?", model.EventStatusPending).Update("status", model.EventStatusSkipped)
+		return
+	}
+
+	delivery := send(ctx, d.db, d.client, &ev, &tenant)
+	if err := d.db.Create(delivery).Error; err != nil {
+		log.Error("[webhook] save delivery failed: ", ev.ID, " ", err)
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{"attempts": ev.Attempts + 1}
+	var delay time.Duration
+	switch {
+	case delivery.Succeeded():
+		updates["status"] = model.EventStatusDelivered
+		updates["deliver_time"] = now
+	case ev.Attempts+1 >= maxAttempts:
+		updates["status"] = model.EventStatusFailed
+		log.Error("[webhook] event ", ev.ID, " failed after ", maxAttempts, " attempts")
+	default:
+		delay = backoff(ev.Attempts + 1)
+		updates["next_time"] = now.Add(delay)
+	}
+	res := d.db.Model(&model.TenantEvent{}).Where("id = ? and status = ?", ev.ID, model.EventStatusPending).Updates(updates)
+	if res.Error != nil {
+		log.Error("[webhook] update event failed: ", ev.ID, " ", res.Error)
+		return
+	}
+	if delay > 0 && res.RowsAffected > 0 {
+		d.queue.EnqueueWithDelay(key, delay)
+	}
+}
+
+// Ping 向租户回调地址同步发送一次测试事件，投递记录的 EventID 为 0
+func Ping(ctx context.Context, db *gorm.DB, tenant *model.Tenant) *model.TenantEventDelivery {
+	ev := &model.TenantEvent{
+		TenantID:  tenant.ID,
+		EventType: model.EventPing,
+		Payload:   `{"message":"ping"}`,
+		AddTime:   time.Now(),
+	}
+	out := send(ctx, db, &http.Client{Timeout: deliverTimeout}, ev, tenant)
+	if err := db.Create(out).Error; err != nil {
+		log.Error("[webhook] save ping delivery failed: ", tenant.ID, " ", err)
+	}
+	return out
+}
+
+// send 签名并投递一次，返回本次投递记录
+func send(ctx context.Context, db *gorm.DB, client *http.Client, ev *model.TenantEvent, tenant *model.Tenant) *model.TenantEventDelivery {
+	out := &model.TenantEventDelivery{
+		EventID:  ev.ID,
+		TenantID: ev.TenantID,
+		Attempt:  ev.Attempts + 1,
+		URL:      tenant.Callback,
+		AddTime:  time.Now(),
+	}
+
+	var api model.SysChannel
+	db.Where("id = ?", tenant.APIID).Limit(1).Find(&api)
+	if api.AppKey == "" {
+		out.ErrMsg = "signing key not found"
+		return out
+	}
+	body, err := json.Marshal(envelope{
+		ID:        ev.ID,
+		Type:      ev.EventType,
+		TenantID:  ev.TenantID,
+		CreatedAt: ev.AddTime.Unix(),
+		Data:      json.RawMessage(ev.Payload),
+	})
+	if err != nil {
+		out.ErrMsg = truncate(err.Error())
+		return out
+	}
+
+	ts := time.Now().Unix()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tenant.Callback, bytes.NewReader(body))
+	if err != nil {
+		out.ErrMsg = truncate(err.Error())
+		return out
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEventID, strconv.FormatUint(ev.ID, 10))
+	req.Header.Set(HeaderEventType, ev.EventType)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+	req.Header.Set(HeaderSignature, Sign(SigningSecret(api.AppKey, tenant.WebhookSalt), ts, body))
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	out.Duration = time.Since(start).Milliseconds()
+	if err != nil {
+		out.ErrMsg = truncate(err.Error())
+		return out
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseLen))
+	out.StatusCode = resp.StatusCode
+	out.Response = truncate(string(respBody))
+	if !out.Succeeded() {
+		out.ErrMsg = fmt.Sprintf("unexpected status %d", resp.StatusCode)
+	}
+	return out
+}
+
+// backoff 第 n 次失败后的重试间隔：10s、20s、40s … 最长 1 小时
+func backoff(n int) time.Duration {
+	delay := baseDelay
+	for i := 1; i < n && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxDelay)
+}
+
+func queueKey(id uint64) string {
+	return queueKeyPrefix + strconv.FormatUint(id, 10)
+}
+
+// truncate 截断到列宽，并去掉截断产生的不完整 UTF-8 字符
+func truncate(s string) string {
+	if len(s) > maxResponseLen {
+		s = s[:maxResponseLen]
+	}
+	return strings.ToValidUTF8(s, "")
+}
@@ -0,0 +1,27 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/reguluswee/walletus/common/model"
+	"gorm.io/gorm"
+)
+
+// Emit 记录一条租户事件，与业务数据在同一事务中写入，保证事件不丢失；由 Dispatcher 异步投递
+func Emit(tx *gorm.DB, tenantID uint64, eventType string, refID uint64, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	return tx.Create(&model.TenantEvent{
+		TenantID:  tenantID,
+		EventType: eventType,
+		RefID:     refID,
+		Payload:   string(body),
+		Status:    model.EventStatusPending,
+		NextTime:  now,
+		AddTime:   now,
+	}).Error
+}
@@ -0,0 +1,44 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// 回调请求头
+const (
+	HeaderEventID   = "X-Walletus-Event-Id"
+	HeaderEventType = "X-Walletus-Event"
+	HeaderTimestamp = "X-Walletus-Timestamp"
+	HeaderSignature = "X-Walletus-Signature"
+)
+
+// SigningSecret 由租户 AppKey 派生的回调签名密钥（hex），AppKey 本身不会出现在回调中
+// salt 为租户的 WebhookSalt，轮换时更换 salt 即可得到新密钥，无需更换 AppKey
+func SigningSecret(appKey, salt string) string {
+	mac := hmac.New(sha256.New, []byte(appKey))
+	mac.Write([]byte("walletus-webhook"))
+	mac.Write([]byte(salt))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sign 以 secret 为密钥对 "{timestamp}.{body}" 计算 HMAC-SHA256，返回 hex 编码的签名
+func Sign(secret string, ts int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(ts, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 校验回调签名，供租户侧及测试使用
+func Verify(secret string, ts int64, body []byte, sig string) bool {
+	expected, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	actual, _ := hex.DecodeString(Sign(secret, ts, body))
+	return hmac.Equal(expected, actual)
+}
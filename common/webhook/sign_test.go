@@ -0,0 +1,44 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	key := SigningSecret("app-key", "")
+	body := []byte(`{"id":1,"type":"deposit.confirmed"}`)
+	sig := Sign(key, 1760000000, body)
+
+	if !Verify(key, 1760000000, body, sig) {
+		t.Fatal("valid signature rejected")
+	}
+	if Verify(key, 1760000001, body, sig) {
+		t.Fatal("signature accepted with another timestamp")
+	}
+	if Verify(SigningSecret("other-key", ""), 1760000000, body, sig) {
+		t.Fatal("signature accepted with another key")
+	}
+	if Verify(SigningSecret("app-key", "rotated"), 1760000000, body, sig) {
+		t.Fatal("signature accepted after secret rotation")
+	}
+	if Verify(key, 1760000000, []byte(`{"id":2}`), sig) {
+		t.Fatal("signature accepted with another body")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	cases := map[int]time.Duration{
+		1:  10 * time.Second,
+		2:  20 * time.Second,
+		4:  80 * time.Second,
+		9:  2560 * time.Second,
+		10: time.Hour,
+		30: time.Hour,
+	}
+	for n, want := range cases {
+		if got := backoff(n); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", n, got, want)
+		}
+	}
+}